@@ -0,0 +1,153 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"cmp"
+	"slices"
+)
+
+// ChangeOp is the operation of a Change
+type ChangeOp uint8
+
+const (
+	ChangeInsert ChangeOp = 1 + iota // insert (New, Obj)
+	ChangeDelete                     // delete (Old, Obj)
+	ChangeUpdate                     // update (Old, Obj) to (New, Obj)
+)
+
+func (op ChangeOp) valid() bool {
+	return op >= ChangeInsert && op <= ChangeUpdate
+}
+
+// Change is an element change of ApplyBatch
+type Change[K comparable, S cmp.Ordered] struct {
+	Op  ChangeOp
+	Obj K
+	Old S // score before change, not used by insert
+	New S // score after change, not used by delete
+}
+
+// ApplyBatch apply `changes` to list and return the outcome of each change
+// in the same order: nil if applied, ErrNotFound if the element to delete
+// or update is not in list, ErrDuplicate if the element to insert or the
+// updated element is already in list, ErrUnknownOp for a bad op, or an
+// error wraps ErrLog if applied but not written to the attached log.
+// Changes are applied in list order of their positions rather than slice
+// order, deletions first and then insertions. Each search continues from
+// the update/rank vector of the previous one instead of head, so close
+// positions are reached without restarting from the top level.
+// An element must not be changed more than once in a batch.
+func (zsl *SkipList[K, S]) ApplyBatch(changes []Change[K, S]) []error {
+	var errs = make([]error, len(changes))
+	zsl.applyBatch(changes, nil, errs, nil)
+	return errs
+}
+
+// applyBatch apply changes whose errs[i] is nil, `fn` is called with index
+// and node of each applied change if not nil, even if its log failed.
+// Elements to delete or update are searched by olds[i] if `olds` is not nil,
+// an updated element is relinked with the new Obj, which may have a
+// different tie-break order but must be the same member.
+func (zsl *SkipList[K, S]) applyBatch(changes []Change[K, S], olds []K, errs []error, fn func(i int, x *SkipListNode[K, S])) {
+	var oldObj = func(i int) K {
+		if olds != nil {
+			return olds[i]
+		}
+		return changes[i].Obj
+	}
+	var deletes, inserts []int
+	for i := range changes {
+		switch {
+		case errs[i] != nil:
+		case !changes[i].Op.valid():
+			errs[i] = ErrUnknownOp
+		case changes[i].Op != ChangeDelete && zsl.acceptMember(changes[i].Obj) != nil:
+			errs[i] = zsl.acceptMember(changes[i].Obj)
+		case changes[i].Op == ChangeInsert:
+			inserts = append(inserts, i)
+		default:
+			deletes = append(deletes, i)
+		}
+	}
+	slices.SortFunc(deletes, func(a, b int) int {
+		return zsl.compareElement(changes[a].Old, oldObj(a), changes[b].Old, oldObj(b))
+	})
+
+	// unlink deleted and moved elements in list order
+	var f finger[K, S]
+	var moved = make(map[int]*SkipListNode[K, S])
+	for _, i := range deletes {
+		var c, old = &changes[i], oldObj(i)
+		zsl.seek(&f, c.Old, old)
+		var x = f.update[0].level[0].forward
+		if x == nil || zsl.compareTo(x, c.Old, old) != 0 {
+			errs[i] = ErrNotFound
+			continue
+		}
+		if c.Op == ChangeUpdate {
+			// update in place if the node is still between its neighbours
+			var prev, next = x.backward, x.level[0].forward
+			if (prev == nil || zsl.compareTo(prev, c.New, c.Obj) < 0) &&
+				(next == nil || zsl.compareTo(next, c.New, c.Obj) > 0) {
+				x.score, x.obj = c.New, c.Obj
+				errs[i] = zsl.logUpdate(c.Old, old, c.New, c.Obj)
+				if fn != nil {
+					fn(i, x)
+				}
+				continue
+			}
+		}
+		zsl.deleteNode(x, f.update[0:])
+		if c.Op == ChangeUpdate {
+			moved[i] = x
+			inserts = append(inserts, i)
+			continue
+		}
+		errs[i] = zsl.logDelete(c.Old, old)
+		if fn != nil {
+			fn(i, x)
+		}
+	}
+	slices.SortFunc(inserts, func(a, b int) int {
+		return zsl.compareElement(changes[a].New, changes[a].Obj, changes[b].New, changes[b].Obj)
+	})
+
+	// link inserted and moved elements in list order, moved nodes are reused
+	f = finger[K, S]{}
+	var restore []int
+	for _, i := range inserts {
+		var c = &changes[i]
+		var x, found = moved[i]
+		if found {
+			x.score, x.obj = c.New, c.Obj
+		} else {
+			x = newSkipListNode(zsl.randLevel(), c.New, c.Obj)
+		}
+		zsl.seek(&f, c.New, c.Obj)
+		if !zsl.linkNode(x, &f) {
+			errs[i] = ErrDuplicate
+			if found {
+				restore = append(restore, i)
+			}
+			continue
+		}
+		if found {
+			errs[i] = zsl.logUpdate(c.Old, oldObj(i), c.New, c.Obj)
+		} else {
+			errs[i] = zsl.logInsert(c.New, c.Obj)
+		}
+		if fn != nil {
+			fn(i, x)
+		}
+	}
+	// (New, Obj) of a moved element is already inside, restore the old one
+	for _, i := range restore {
+		var x = moved[i]
+		x.score, x.obj = changes[i].Old, oldObj(i)
+		zsl.insertNode(x)
+	}
+	zsl.check()
+}
@@ -0,0 +1,182 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkScores check elements of list are exactly `scores` of uuid
+func checkScores(t *testing.T, zsl *ZSkipList, scores map[uint64]uint32) {
+	if err := zsl.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var uuids = make([]uint64, 0, len(scores))
+	for uuid := range scores {
+		uuids = append(uuids, uuid)
+	}
+	slices.SortFunc(uuids, func(a, b uint64) int {
+		if c := cmp.Compare(scores[a], scores[b]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	if zsl.Len() != len(uuids) {
+		t.Fatalf("unexpected element count, %d != %d", zsl.Len(), len(uuids))
+	}
+	var x = zsl.HeaderNode()
+	for _, uuid := range uuids {
+		if x.Member().Uuid() != uuid || x.Score() != scores[uuid] {
+			t.Fatalf("element mismatch, %d-%d != %d-%d", x.Member().Uuid(), x.Score(), uuid, scores[uuid])
+		}
+		x = x.Next()
+	}
+}
+
+func TestZSkipListApplyBatch(t *testing.T) {
+	const units = 5000
+	var set = makeTestPlayers(units, 1000, true)
+	var zsl = NewZSkipList()
+	var scores = make(map[uint64]uint32, units)
+	for _, v := range set {
+		zsl.Insert(v.Populace, v)
+		scores[v.Uid] = v.Populace
+	}
+
+	var changes []Change[RankInterface, uint32]
+	var expected []error
+	var i = 0
+	for _, v := range set {
+		switch i % 5 {
+		case 0:
+			changes = append(changes, Change[RankInterface, uint32]{Op: ChangeDelete, Obj: v, Old: v.Populace})
+			delete(scores, v.Uid)
+			expected = append(expected, nil)
+		case 1, 2:
+			var score = uint32(rand.Int()%1000) + 1
+			changes = append(changes, Change[RankInterface, uint32]{Op: ChangeUpdate, Obj: v, Old: v.Populace, New: score})
+			scores[v.Uid] = score
+			expected = append(expected, nil)
+		case 3:
+			// wrong old score
+			changes = append(changes, Change[RankInterface, uint32]{Op: ChangeUpdate, Obj: v, Old: v.Populace + 1000, New: 1})
+			expected = append(expected, ErrNotFound)
+		case 4:
+			changes = append(changes, Change[RankInterface, uint32]{Op: ChangeInsert, Obj: v, New: v.Populace})
+			expected = append(expected, ErrDuplicate)
+		}
+		i++
+	}
+	for uid := uint64(1); uid <= units/2; uid++ {
+		var score = uint32(rand.Int()%1000) + 1
+		changes = append(changes, Change[RankInterface, uint32]{Op: ChangeInsert, Obj: &testPlayer{Uid: uid}, New: score})
+		scores[uid] = score
+		expected = append(expected, nil)
+	}
+	changes = append(changes, Change[RankInterface, uint32]{Op: ChangeDelete, Obj: &testPlayer{Uid: units}, Old: 1})
+	expected = append(expected, ErrNotFound)
+	changes = append(changes, Change[RankInterface, uint32]{Obj: &testPlayer{Uid: units + 1}})
+	expected = append(expected, ErrUnknownOp)
+	rand.Shuffle(len(changes), func(i, j int) {
+		changes[i], changes[j] = changes[j], changes[i]
+		expected[i], expected[j] = expected[j], expected[i]
+	})
+
+	var errs = zsl.ApplyBatch(changes)
+	for i, err := range errs {
+		if !errors.Is(err, expected[i]) {
+			t.Fatalf("change %d %v: %v != %v", i, changes[i], err, expected[i])
+		}
+	}
+	checkScores(t, zsl, scores)
+}
+
+func TestZSetApplyBatch(t *testing.T) {
+	const units = 5000
+	var set = makeTestPlayers(units, 1000, true)
+	var codec = UuidCodec(func(uuid uint64) (RankInterface, error) {
+		return &testPlayer{Uid: uuid}, nil
+	})
+	var wal bytes.Buffer
+	var zs = NewZSet()
+	zs.SetMemberCodec(codec)
+	if err := zs.AttachLog(&wal); err != nil {
+		t.Fatalf("AttachLog: %v", err)
+	}
+	var scores = make(map[uint64]uint32, units)
+	for _, v := range set {
+		zs.Add(v.Populace, v)
+		scores[v.Uid] = v.Populace
+	}
+
+	// score flush of a tick, old scores are taken from set
+	var changes []Change[RankInterface, uint32]
+	for _, v := range set {
+		if len(changes) >= units/2 {
+			break
+		}
+		var score = scores[v.Uid] + uint32(rand.Int()%10)
+		changes = append(changes, Change[RankInterface, uint32]{Op: ChangeUpdate, Obj: v, New: score})
+		scores[v.Uid] = score
+	}
+	var removed = changes[0].Obj
+	changes[0] = Change[RankInterface, uint32]{Op: ChangeDelete, Obj: removed}
+	delete(scores, removed.Uuid())
+	changes = append(changes,
+		Change[RankInterface, uint32]{Op: ChangeInsert, Obj: &testPlayer{Uid: 1}, New: 10},
+		Change[RankInterface, uint32]{Op: ChangeUpdate, Obj: &testPlayer{Uid: 1}, New: 20},
+		Change[RankInterface, uint32]{Op: ChangeUpdate, Obj: &testPlayer{Uid: 2}, New: 20},
+	)
+	scores[1] = 10
+
+	var errs = zs.ApplyBatch(changes)
+	for i, err := range errs[:len(errs)-2] {
+		if err != nil {
+			t.Fatalf("change %d %v: %v", i, changes[i], err)
+		}
+	}
+	if !errors.Is(errs[len(errs)-2], ErrDuplicate) || !errors.Is(errs[len(errs)-1], ErrNotFound) {
+		t.Fatalf("unexpected outcomes %v", errs[len(errs)-2:])
+	}
+	checkScores(t, zs.List(), scores)
+	for uuid, score := range scores {
+		if s, found := zs.Score(uuid); !found || s != score {
+			t.Fatalf("score of %d: %d != %d", uuid, s, score)
+		}
+	}
+	if zs.Contains(removed.Uuid()) {
+		t.Fatalf("removed object %v still in set", removed)
+	}
+
+	var replayed = NewZSet()
+	replayed.SetMemberCodec(codec)
+	if err := replayed.Replay(&wal); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	checkSameList(t, zs.List(), replayed.List())
+}
+
+func BenchmarkZSkipListApplyBatch(b *testing.B) {
+	const units = 100000
+	var zsl, ranks = makeSortedTestList(units, 10000)
+	var changes = make([]Change[RankInterface, uint32], 0, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		changes = changes[:0]
+		var start = rand.Int() % units
+		for j := 0; j < cap(changes); j++ {
+			var v = ranks[(start+j*(units/cap(changes)))%units]
+			var score = v.Populace + 1
+			changes = append(changes, Change[RankInterface, uint32]{Op: ChangeUpdate, Obj: v, Old: v.Populace, New: score})
+			v.Populace = score
+		}
+		zsl.ApplyBatch(changes)
+	}
+}
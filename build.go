@@ -0,0 +1,107 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+)
+
+// sortedBuilder append elements to the tail of list in list order, it keeps
+// the last node and its rank of every level, so each append is O(1) expected.
+type sortedBuilder[K comparable, S cmp.Ordered] struct {
+	zsl    *SkipList[K, S]
+	update [ZSKIPLIST_MAXLEVEL_LIMIT]*SkipListNode[K, S] // last node of each level
+	rank   [ZSKIPLIST_MAXLEVEL_LIMIT]int                 // rank of update[i]
+}
+
+func newSortedBuilder[K comparable, S cmp.Ordered](zsl *SkipList[K, S]) *sortedBuilder[K, S] {
+	var b = &sortedBuilder[K, S]{zsl: zsl}
+	var rank = 0
+	var x = zsl.head
+	for i := len(zsl.head.level) - 1; i >= 0; i-- {
+		if i < zsl.level {
+			for x.level[i].forward != nil {
+				rank += x.level[i].span
+				x = x.level[i].forward
+			}
+		}
+		b.update[i] = x
+		b.rank[i] = rank
+	}
+	return b
+}
+
+// append link (score, obj) after the tail, it must be greater than the tail
+// in list order.
+func (b *sortedBuilder[K, S]) append(score S, obj K) (*SkipListNode[K, S], error) {
+	var zsl = b.zsl
+	if err := zsl.acceptMember(obj); err != nil {
+		return nil, err
+	}
+	if zsl.tail != nil && zsl.compareTo(zsl.tail, score, obj) >= 0 {
+		return nil, fmt.Errorf("%w: <%s %v> after %s", ErrOutOfOrder, memberString(obj), score, nodeString(zsl.tail))
+	}
+	var x = newSkipListNode(zsl.randLevel(), score, obj)
+	var level = len(x.level)
+	if level > zsl.level {
+		for i := zsl.level; i < level; i++ {
+			zsl.head.level[i].span = zsl.length
+		}
+		zsl.level = level
+	}
+	var rank = zsl.length + 1
+	for i := 0; i < level; i++ {
+		b.update[i].level[i].forward = x
+		x.level[i].backward = b.update[i]
+		b.update[i].level[i].span = rank - b.rank[i]
+		b.update[i] = x
+		b.rank[i] = rank
+	}
+	// increment span for untouched levels
+	for i := level; i < zsl.level; i++ {
+		b.update[i].level[i].span++
+	}
+	x.backward = zsl.tail
+	zsl.tail = x
+	zsl.length++
+	return x, nil
+}
+
+// AppendSorted append elements of `seq` after the last element of list in
+// O(1) expected each, without searching from head. Elements must be in list
+// order and after the last element, e.g. rows of `ORDER BY score, id`.
+// Returns an error wraps ErrOutOfOrder at the first out-of-order element,
+// elements before it are kept in list. An error wraps ErrLog is returned if
+// elements are appended but not written to the attached log.
+func (zsl *SkipList[K, S]) AppendSorted(seq iter.Seq2[K, S]) error {
+	defer zsl.check()
+	var b = newSortedBuilder(zsl)
+	for obj, score := range seq {
+		if _, err := b.append(score, obj); err != nil {
+			return err
+		}
+		zsl.logInsert(score, obj)
+	}
+	return zsl.LogErr()
+}
+
+// BuildFromSorted replace all elements of list by elements of `seq` in O(N),
+// see AppendSorted.
+func (zsl *SkipList[K, S]) BuildFromSorted(seq iter.Seq2[K, S]) error {
+	zsl.clear()
+	return zsl.AppendSorted(seq)
+}
+
+// clear remove all elements of list, deletions are recorded to log
+func (zsl *SkipList[K, S]) clear() {
+	if zsl.oplog != nil {
+		for x := zsl.head.level[0].forward; x != nil; x = x.level[0].forward {
+			zsl.logDelete(x.score, x.obj)
+		}
+	}
+	zsl.reset()
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"bytes"
+	"errors"
+	"iter"
+	"sort"
+	"testing"
+)
+
+// sortedSeq return an iterator over (member, score) of `ranks`
+func sortedSeq(ranks []*testPlayer) iter.Seq2[RankInterface, uint32] {
+	return func(yield func(RankInterface, uint32) bool) {
+		for _, v := range ranks {
+			if !yield(v, v.Populace) {
+				return
+			}
+		}
+	}
+}
+
+func TestZSkipListBuildFromSorted(t *testing.T) {
+	const units = 10000
+	var zsl, ranks = makeSortedTestList(units, 1000)
+
+	var built = NewZSkipList(WithDebug())
+	built.Insert(1, &testPlayer{Uid: 1})
+	if err := built.BuildFromSorted(sortedSeq(ranks[:units/2])); err != nil {
+		t.Fatalf("BuildFromSorted: %v", err)
+	}
+	if err := built.AppendSorted(sortedSeq(ranks[units/2:])); err != nil {
+		t.Fatalf("AppendSorted: %v", err)
+	}
+	checkSameList(t, zsl, built)
+	for rank, v := range ranks {
+		if built.GetRank(v.Populace, v) != rank+1 {
+			t.Fatalf("rank of %v mismatch", v)
+		}
+	}
+	// out-of-order element stops the build, elements before it are kept
+	built = NewZSkipList()
+	var input = []*testPlayer{ranks[0], ranks[1], ranks[3], ranks[2], ranks[4]}
+	if err := built.BuildFromSorted(sortedSeq(input)); !errors.Is(err, ErrOutOfOrder) {
+		t.Fatalf("BuildFromSorted out of order: %v", err)
+	}
+	if err := built.Validate(); err != nil || built.Len() != 3 {
+		t.Fatalf("unexpected list after failed build, %d: %v", built.Len(), err)
+	}
+	if err := built.AppendSorted(sortedSeq(ranks[3:4])); !errors.Is(err, ErrOutOfOrder) {
+		t.Fatalf("AppendSorted duplicate: %v", err)
+	}
+
+	var desc = NewZSkipList(WithDescending())
+	// ties are still broken by ascending uuid
+	var reversed = append([]*testPlayer(nil), ranks...)
+	sort.SliceStable(reversed, func(i, j int) bool {
+		return reversed[i].Populace > reversed[j].Populace
+	})
+	if err := desc.BuildFromSorted(sortedSeq(reversed)); err != nil {
+		t.Fatalf("BuildFromSorted descending: %v", err)
+	}
+	if err := desc.Validate(); err != nil || desc.Len() != units {
+		t.Fatalf("unexpected descending list, %d: %v", desc.Len(), err)
+	}
+
+	// list is still usable after build
+	var set = make(map[uint64]*testPlayer, units)
+	for _, v := range ranks {
+		set[v.Uid] = v
+	}
+	built = NewZSkipList()
+	if err := built.BuildFromSorted(sortedSeq(ranks)); err != nil {
+		t.Fatalf("BuildFromSorted: %v", err)
+	}
+	manyUpdate(t, built, set, units/2)
+	if err := built.Validate(); err != nil {
+		t.Fatalf("Validate after update: %v", err)
+	}
+}
+
+func TestZSetBuildFromSorted(t *testing.T) {
+	const units = 5000
+	var _, ranks = makeSortedTestList(units, 1000)
+	var codec = UuidCodec(func(uuid uint64) (RankInterface, error) {
+		return &testPlayer{Uid: uuid}, nil
+	})
+	var wal bytes.Buffer
+	var zs = NewZSet()
+	zs.SetMemberCodec(codec)
+	if err := zs.AttachLog(&wal); err != nil {
+		t.Fatalf("AttachLog: %v", err)
+	}
+	zs.Add(1, &testPlayer{Uid: 1})
+	if err := zs.BuildFromSorted(sortedSeq(ranks)); err != nil {
+		t.Fatalf("BuildFromSorted: %v", err)
+	}
+	if zs.Len() != units || zs.Contains(1) {
+		t.Fatalf("unexpected set after build, %d", zs.Len())
+	}
+	for rank, v := range ranks {
+		if zs.Rank(v.Uid) != rank+1 {
+			t.Fatalf("rank of %v mismatch", v)
+		}
+	}
+
+	// an object already in set is rejected even if it is in order
+	var last = ranks[units-1]
+	var dup = &testPlayer{Uid: last.Uid, Populace: last.Populace + 1}
+	if err := zs.AppendSorted(sortedSeq([]*testPlayer{dup})); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("AppendSorted duplicate: %v", err)
+	}
+
+	var replayed = NewZSet()
+	replayed.SetMemberCodec(codec)
+	if err := replayed.Replay(&wal); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	checkSameList(t, zs.List(), replayed.List())
+}
+
+func BenchmarkZSkipListBuildFromSorted(b *testing.B) {
+	var _, ranks = makeSortedTestList(100000, 10000)
+	var zsl = NewZSkipList()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		zsl.BuildFromSorted(sortedSeq(ranks))
+	}
+}
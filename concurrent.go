@@ -0,0 +1,257 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"context"
+	"sync"
+)
+
+// RankEntry is a copy of an element taken under lock
+type RankEntry struct {
+	Obj   RankInterface
+	Score uint32
+	Rank  int // 1-based rank in list order
+}
+
+// ConcurrentZSkipList is a ZSet guarded by sync.RWMutex, it is safe for
+// many readers and writers.
+//
+// No method returns a *ZSkipListNode: writers update node scores in place
+// and relink nodes, so a node is only safe to dereference while the lock is
+// held. Read methods return RankEntry copies instead, and nodes passed to
+// the callback of View/Update must not be retained after it returns.
+type ConcurrentZSkipList struct {
+	mu   sync.RWMutex
+	zs   *ZSet
+	wait chan struct{} // closed when objects are added, nil if no waiter
+}
+
+func NewConcurrentZSkipList(opts ...Option) *ConcurrentZSkipList {
+	return &ConcurrentZSkipList{
+		zs: NewZSet(opts...),
+	}
+}
+
+// Len return # of items in list
+func (c *ConcurrentZSkipList) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.zs.Len()
+}
+
+// Add add obj with score, or update its score if already exist
+func (c *ConcurrentZSkipList) Add(score uint32, obj RankInterface) {
+	c.mu.Lock()
+	c.zs.Add(score, obj)
+	c.notify()
+	c.mu.Unlock()
+}
+
+// Remove remove the object with `uuid`, return false if not found
+func (c *ConcurrentZSkipList) Remove(uuid uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.zs.Remove(uuid) != nil
+}
+
+// PopMin remove and return at most `n` entries of the lowest scores,
+// lowest first, see ZSkipList.PopMin.
+func (c *ConcurrentZSkipList) PopMin(n int) []RankEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pop(n, false)
+}
+
+// PopMax remove and return at most `n` entries of the highest scores,
+// highest first, see ZSkipList.PopMax.
+func (c *ConcurrentZSkipList) PopMax(n int) []RankEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pop(n, true)
+}
+
+// BlockingPopMin remove and return the entry of the lowest score like
+// BZPOPMIN, it waits until an object is added if the list is empty.
+// Returns ctx.Err() if `ctx` is done before an entry is popped.
+func (c *ConcurrentZSkipList) BlockingPopMin(ctx context.Context) (RankEntry, error) {
+	return c.blockingPop(ctx, false)
+}
+
+// BlockingPopMax remove and return the entry of the highest score like
+// BZPOPMAX, see BlockingPopMin.
+func (c *ConcurrentZSkipList) BlockingPopMax(ctx context.Context) (RankEntry, error) {
+	return c.blockingPop(ctx, true)
+}
+
+func (c *ConcurrentZSkipList) blockingPop(ctx context.Context, highest bool) (RankEntry, error) {
+	for {
+		c.mu.Lock()
+		if entries := c.pop(1, highest); len(entries) > 0 {
+			c.mu.Unlock()
+			return entries[0], nil
+		}
+		if c.wait == nil {
+			c.wait = make(chan struct{})
+		}
+		var wait = c.wait
+		c.mu.Unlock()
+
+		// all waiters are woken up by an add, the losers wait again
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return RankEntry{}, ctx.Err()
+		}
+	}
+}
+
+// pop remove at most `n` entries of the lowest or highest scores under
+// write lock, rank of an entry is its rank just before removed.
+func (c *ConcurrentZSkipList) pop(n int, highest bool) []RankEntry {
+	var fromTail = highest != c.zs.zsl.descending
+	var rank = 1
+	var step = 0
+	if fromTail {
+		rank, step = c.zs.Len(), -1
+	}
+	var nodes []*ZSkipListNode
+	if highest {
+		nodes = c.zs.PopMax(n)
+	} else {
+		nodes = c.zs.PopMin(n)
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	return copyEntries(nodes, rank, step)
+}
+
+// notify wake up all waiters of blocking pop under write lock
+func (c *ConcurrentZSkipList) notify() {
+	if c.wait != nil && c.zs.Len() > 0 {
+		close(c.wait)
+		c.wait = nil
+	}
+}
+
+// Contains test if an object with `uuid` is in list
+func (c *ConcurrentZSkipList) Contains(uuid uint64) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.zs.Contains(uuid)
+}
+
+// Score return score of the object with `uuid`
+func (c *ConcurrentZSkipList) Score(uuid uint64) (uint32, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.zs.Score(uuid)
+}
+
+// Rank return 1-based rank of the object with `uuid`, 0 if not found
+func (c *ConcurrentZSkipList) Rank(uuid uint64) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.zs.Rank(uuid)
+}
+
+// Ranks return ranks of many objects under one read lock,
+// rank is 0 if the object is not found.
+func (c *ConcurrentZSkipList) Ranks(uuids []uint64) []int {
+	var ranks = make([]int, len(uuids))
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for i, uuid := range uuids {
+		ranks[i] = c.zs.Rank(uuid)
+	}
+	return ranks
+}
+
+// Entries return entries of many objects under one read lock,
+// missing objects are skipped.
+func (c *ConcurrentZSkipList) Entries(uuids []uint64) []RankEntry {
+	var entries = make([]RankEntry, 0, len(uuids))
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, uuid := range uuids {
+		if node, found := c.zs.dict[uuid]; found {
+			entries = append(entries, RankEntry{
+				Obj:   node.obj,
+				Score: node.score,
+				Rank:  c.zs.zsl.RankOf(node),
+			})
+		}
+	}
+	return entries
+}
+
+// GetElementByRank return the entry at 1-based `rank`
+func (c *ConcurrentZSkipList) GetElementByRank(rank int) (RankEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if rank < 1 {
+		return RankEntry{}, false
+	}
+	var node = c.zs.zsl.GetElementByRank(rank)
+	if node == nil {
+		return RankEntry{}, false
+	}
+	return RankEntry{Obj: node.obj, Score: node.score, Rank: rank}, true
+}
+
+// RangeByRank return entries by 0-based index range, see ZSkipList.RangeByRank
+func (c *ConcurrentZSkipList) RangeByRank(start, stop int, reverse bool) []RankEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var nodes = c.zs.zsl.RangeByRank(start, stop, reverse)
+	if len(nodes) == 0 {
+		return nil
+	}
+	var rank = c.zs.zsl.RankOf(nodes[0])
+	var step = 1
+	if reverse {
+		step = -1
+	}
+	return copyEntries(nodes, rank, step)
+}
+
+// RangeByScore return entries in range, see ZSkipList.RangeByScore
+func (c *ConcurrentZSkipList) RangeByScore(r RangeSpec[uint32], offset, limit int) []RankEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var nodes = c.zs.zsl.RangeByScore(r, offset, limit)
+	if len(nodes) == 0 {
+		return nil
+	}
+	var rank = c.zs.zsl.RankOf(nodes[0])
+	return copyEntries(nodes, rank, 1)
+}
+
+// View call `fn` with the underlying set under read lock,
+// `fn` must not modify the set or retain any node.
+func (c *ConcurrentZSkipList) View(fn func(zs *ZSet)) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	fn(c.zs)
+}
+
+// Update call `fn` with the underlying set under write lock,
+// `fn` must not retain any node.
+func (c *ConcurrentZSkipList) Update(fn func(zs *ZSet)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn(c.zs)
+	c.notify()
+}
+
+func copyEntries(nodes []*ZSkipListNode, rank, step int) []RankEntry {
+	var entries = make([]RankEntry, len(nodes))
+	for i, node := range nodes {
+		entries[i] = RankEntry{Obj: node.obj, Score: node.score, Rank: rank}
+		rank += step
+	}
+	return entries
+}
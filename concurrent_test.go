@@ -0,0 +1,128 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentZSkipList(t *testing.T) {
+	const units = 1000
+	var set = makeTestPlayers(units, 1000, true)
+	var czsl = NewConcurrentZSkipList(WithDescending())
+	var uuids = make([]uint64, 0, units)
+	for _, v := range set {
+		czsl.Add(v.Populace, v)
+		uuids = append(uuids, v.Uid)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				for k, rank := range czsl.Ranks(uuids) {
+					if rank < 1 || rank > units {
+						t.Errorf("rank of %d out of range: %d", uuids[k], rank)
+						return
+					}
+				}
+				var entries = czsl.RangeByRank(0, 9, false)
+				for k := 1; k < len(entries); k++ {
+					if entries[k].Score > entries[k-1].Score || entries[k].Rank != k+1 {
+						t.Errorf("unexpected range entry %d: %+v", k, entries[k])
+						return
+					}
+				}
+			}
+		}()
+	}
+	for j := 0; j < 5000; j++ {
+		var v = &testPlayer{Uid: uuids[rand.Int()%units], Populace: uint32(rand.Int() % 1000)}
+		czsl.Add(v.Populace, v)
+	}
+	wg.Wait()
+
+	if czsl.Len() != units {
+		t.Fatalf("unexpected element count, %d != %d", czsl.Len(), units)
+	}
+	for _, e := range czsl.Entries(uuids[:10]) {
+		if score, _ := czsl.Score(e.Obj.Uuid()); score != e.Score || czsl.Rank(e.Obj.Uuid()) != e.Rank {
+			t.Fatalf("unexpected entry %+v", e)
+		}
+		if got, ok := czsl.GetElementByRank(e.Rank); !ok || got != e {
+			t.Fatalf("element at rank %d: %+v != %+v", e.Rank, got, e)
+		}
+	}
+	if !czsl.Remove(uuids[0]) || czsl.Contains(uuids[0]) || czsl.Remove(uuids[0]) {
+		t.Fatalf("remove %d failed", uuids[0])
+	}
+}
+
+func TestConcurrentZSkipListBlockingPop(t *testing.T) {
+	var czsl = NewConcurrentZSkipList()
+	var ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := czsl.BlockingPopMin(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("BlockingPopMin on empty list: %v", err)
+	}
+
+	// delayed jobs keyed by due time
+	const jobs = 100
+	ctx, cancel = context.WithCancel(context.Background())
+	var results = make(chan RankEntry, jobs)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var e, err = czsl.BlockingPopMin(ctx)
+				if err != nil {
+					if err != context.Canceled {
+						t.Errorf("BlockingPopMin: %v", err)
+					}
+					return
+				}
+				results <- e
+			}
+		}()
+	}
+	for i := 0; i < jobs; i++ {
+		czsl.Add(uint32(i), &testPlayer{Uid: uint64(i + 1)})
+	}
+	var popped = make(map[uint64]bool)
+	for i := 0; i < jobs; i++ {
+		var e = <-results
+		if popped[e.Obj.Uuid()] || e.Rank != 1 {
+			t.Fatalf("unexpected popped entry %+v", e)
+		}
+		popped[e.Obj.Uuid()] = true
+	}
+	cancel()
+	wg.Wait()
+	if czsl.Len() != 0 {
+		t.Fatalf("unexpected element count, %d", czsl.Len())
+	}
+
+	for i := 0; i < 5; i++ {
+		czsl.Add(uint32(i), &testPlayer{Uid: uint64(i + 1)})
+	}
+	var entries = czsl.PopMax(2)
+	if len(entries) != 2 || entries[0].Score != 4 || entries[0].Rank != 5 || entries[1].Rank != 4 {
+		t.Fatalf("unexpected pop max %+v", entries)
+	}
+	if e, err := czsl.BlockingPopMax(context.Background()); err != nil || e.Score != 2 {
+		t.Fatalf("BlockingPopMax: %+v %v", e, err)
+	}
+	if entries = czsl.PopMin(5); len(entries) != 2 || entries[1].Score != 1 || entries[1].Rank != 1 {
+		t.Fatalf("unexpected pop min %+v", entries)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"errors"
+)
+
+var (
+	ErrNotFound        = errors.New("zskiplist: element not found")
+	ErrDuplicate       = errors.New("zskiplist: duplicate element")
+	ErrScoreMismatch   = errors.New("zskiplist: score mismatch")
+	ErrCorrupted       = errors.New("zskiplist: list corrupted")
+	ErrInvalidSnapshot = errors.New("zskiplist: invalid snapshot")
+	ErrChecksum        = errors.New("zskiplist: checksum mismatch")
+	ErrNoMemberCodec   = errors.New("zskiplist: no member codec")
+	ErrOutOfOrder      = errors.New("zskiplist: element out of order")
+	ErrInvalidLog      = errors.New("zskiplist: invalid log record")
+	ErrLog             = errors.New("zskiplist: write log failed")
+	ErrInvalidCursor   = errors.New("zskiplist: invalid cursor")
+	ErrUnknownOp       = errors.New("zskiplist: unknown change op")
+	ErrInvalidLexRange = errors.New("zskiplist: invalid lex range item")
+	ErrNotLexMember    = errors.New("zskiplist: member has no lex key")
+
+	errMalformed = errors.New("malformed data")
+)
@@ -45,18 +45,23 @@ func ExampleZSkipList() {
 	fmt.Printf("%v\n", zsl)
 
 	//获取角色的排行信息
-	var rank = zsl.GetRank(p1.score, p1) // in ascend order
-	var myRank = zsl.Len() - rank + 1    // get descend rank
+	var rank = zsl.GetRank(p1.score, p1)   // in ascend order
+	var myRank = zsl.RevRank(p1.score, p1) // get descend rank
 	fmt.Printf("rank of %s: %d\n", p1.name, myRank)
 
 	//根据排行获取角色信息
 	var node = zsl.GetElementByRank(rank)
-	var player = playerMap[node.Obj.Uuid()]
+	var player = playerMap[node.Member().Uuid()]
 	fmt.Printf("rank at %d is: %s\n", rank, player.name)
 
+	//获取排行前3的角色
+	for i, node := range zsl.RangeByRank(0, 2, true) {
+		fmt.Printf("top %d: %v\n", i+1, node.Member())
+	}
+
 	//遍历整个zskiplist
 	zsl.Walk(true, func(rank int, v RankInterface) bool {
-		fmt.Printf("rank %d: %v", v)
+		fmt.Printf("rank %d: %v\n", rank, v)
 		return true
 	})
 
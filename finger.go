@@ -0,0 +1,186 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"cmp"
+)
+
+// searchFrom move finger `f` to the position of element (score, obj) by a
+// finger search from node `x`: it climbs to the top level of every node it
+// passes until the position is between a node and its forward link, then
+// descends as a search from head. Ranks of `f` are relative to `x`, and only
+// levels up to the returned level are set, above which the position has the
+// same predecessors as `x`. It costs O(log d) for distance d.
+// `moved` is a node unlinked after `x` at its levels, spans of the upper
+// levels crossing it are decreased on the way up.
+func (zsl *SkipList[K, S]) searchFrom(x *SkipListNode[K, S], score S, obj K, f *finger[K, S], moved *SkipListNode[K, S]) int {
+	var pred *SkipListNode[K, S] // predecessor of `moved` at level i
+	if moved != nil {
+		pred = moved.level[len(moved.level)-1].backward
+	}
+	var i, rank = 0, 0
+	for {
+		for i+1 < zsl.level && i+1 < len(x.level) {
+			i++
+			if moved != nil && i >= len(moved.level) {
+				pred = predAbove(pred, i)
+				pred.level[i].span--
+			}
+		}
+		if x != zsl.head && zsl.compareTo(x, score, obj) >= 0 {
+			var prev = x.level[i].backward
+			rank -= prev.level[i].span
+			x = prev
+		} else if next := x.level[i].forward; next != nil && zsl.compareTo(next, score, obj) < 0 {
+			rank += x.level[i].span
+			x = next
+		} else {
+			break
+		}
+	}
+	var level = i
+	for ; i >= 0; i-- {
+		for x.level[i].forward != nil &&
+			zsl.compareTo(x.level[i].forward, score, obj) < 0 {
+			rank += x.level[i].span
+			x = x.level[i].forward
+		}
+		f.update[i] = x
+		f.rank[i] = rank
+	}
+	return level
+}
+
+// predAbove return the last node at level i before node `x` of level i,
+// including `x`, `x` is a node of at least level i.
+func predAbove[K comparable, S cmp.Ordered](x *SkipListNode[K, S], i int) *SkipListNode[K, S] {
+	for len(x.level) <= i {
+		x = x.level[i-1].backward
+	}
+	return x
+}
+
+// SeekFrom return the first node that is not before (score, obj) in list
+// order and its rank minus rank of node `x` by a finger search from `x`,
+// the node is nil if all elements are before it. It costs O(log d) for
+// distance d between them rather than O(log N) of a search from head.
+func (zsl *SkipList[K, S]) SeekFrom(x *SkipListNode[K, S], score S, obj K) (*SkipListNode[K, S], int) {
+	var f finger[K, S]
+	zsl.searchFrom(x, score, obj, &f, nil)
+	return f.update[0].level[0].forward, f.rank[0] + 1
+}
+
+// UpdateNodeScore update the score of node `x` in list to `newScore`, it is
+// the same as UpdateScore but costs O(log d) for a move of d places since
+// the node need not be searched.
+func (zsl *SkipList[K, S]) UpdateNodeScore(x *SkipListNode[K, S], newScore S) *SkipListNode[K, S] {
+	x, _ = zsl.updateNode(x, newScore, x.obj)
+	return x
+}
+
+// updateNode move node `x` to (newScore, newObj), return ErrDuplicate if
+// the new element is already in list, or `x` with the error of log.
+func (zsl *SkipList[K, S]) updateNode(x *SkipListNode[K, S], newScore S, newObj K) (*SkipListNode[K, S], error) {
+	if err := zsl.acceptMember(newObj); err != nil {
+		return nil, err
+	}
+	var curScore, obj = x.score, x.obj
+
+	// If the node, after the score update, would be still exactly at the
+	// same position, we can just update the score without actually
+	// removing and re-inserting the element in the skiplist.
+	var prev, next = x.backward, x.level[0].forward
+	if (prev == nil || zsl.compareTo(prev, newScore, newObj) < 0) &&
+		(next == nil || zsl.compareTo(next, newScore, newObj) > 0) {
+		x.score = newScore
+		x.obj = newObj
+	} else if !zsl.moveNode(x, newScore, newObj) {
+		return nil, ErrDuplicate
+	}
+	var err = zsl.logUpdate(curScore, obj, newScore, newObj)
+	zsl.check()
+	return x, err
+}
+
+// moveNode relink node `x` at (newScore, newObj) by a finger search from its
+// old position, the node and its level are reused. Returns false if the new
+// element is already in list, `x` is kept as is.
+func (zsl *SkipList[K, S]) moveNode(x *SkipListNode[K, S], newScore S, newObj K) bool {
+	var height = len(x.level)
+	var start = x.level[0].backward
+
+	// unlink x at its levels, ranks are relative to its predecessor
+	var f finger[K, S]
+	for i := 0; i < height; i++ {
+		var prev = x.level[i].backward
+		f.update[i] = prev
+		f.rank[i] = 1 - prev.level[i].span
+		prev.level[i].span += x.level[i].span - 1
+		prev.level[i].forward = x.level[i].forward
+		if x.level[i].forward != nil {
+			x.level[i].forward.level[i].backward = prev
+		}
+	}
+	if next := x.level[0].forward; next != nil {
+		next.backward = x.backward
+	} else {
+		zsl.tail = x.backward
+	}
+	var level = zsl.searchFrom(start, newScore, newObj, &f, x)
+
+	if next := f.update[0].level[0].forward; next != nil && zsl.compareTo(next, newScore, newObj) == 0 {
+		// restore spans of upper levels and links of x
+		var pred = x.level[height-1].backward
+		for i := height; i <= level; i++ {
+			pred = predAbove(pred, i)
+			pred.level[i].span++
+		}
+		for i := 0; i < height; i++ {
+			var prev = x.level[i].backward
+			prev.level[i].span -= x.level[i].span - 1
+			prev.level[i].forward = x
+			if x.level[i].forward != nil {
+				x.level[i].forward.level[i].backward = x
+			}
+		}
+		if next := x.level[0].forward; next != nil {
+			next.backward = x
+		} else {
+			zsl.tail = x
+		}
+		return false
+	}
+
+	// above the level of search, x has the same predecessors as before
+	x.score = newScore
+	x.obj = newObj
+	var update, rank = &f.update, &f.rank
+	for i := 0; i < height; i++ {
+		x.level[i].forward = update[i].level[i].forward
+		x.level[i].backward = update[i]
+		if x.level[i].forward != nil {
+			x.level[i].forward.level[i].backward = x
+		}
+		update[i].level[i].forward = x
+		x.level[i].span = update[i].level[i].span - (rank[0] - rank[i])
+		update[i].level[i].span = (rank[0] - rank[i]) + 1
+	}
+	// increment span of the levels decreased by search
+	for i := height; i <= level; i++ {
+		update[i].level[i].span++
+	}
+	if update[0] != zsl.head {
+		x.backward = update[0]
+	} else {
+		x.backward = nil
+	}
+	if x.level[0].forward != nil {
+		x.level[0].forward.backward = x
+	} else {
+		zsl.tail = x
+	}
+	return true
+}
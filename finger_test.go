@@ -0,0 +1,110 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"cmp"
+	"math/rand"
+	"testing"
+)
+
+func TestZSkipListSeekFrom(t *testing.T) {
+	const units = 5000
+	var zsl, ranks = makeSortedTestList(units, 1000)
+	for i := 0; i < 2000; i++ {
+		var from = rand.Int() % units
+		var to = from + rand.Int()%64 - 32
+		if to < 0 || to >= units {
+			continue
+		}
+		var x = zsl.GetElementByRank(from + 1)
+		var v = ranks[to]
+		node, delta := zsl.SeekFrom(x, v.Populace, v)
+		if node == nil || node.Member() != v || delta != to-from {
+			t.Fatalf("seek %v from rank %d: %v %d", v, from+1, node, delta)
+		}
+		// a missing element positions at the next one
+		node, delta = zsl.SeekFrom(x, v.Populace, &testPlayer{Uid: v.Uid + 1})
+		if to+1 < units && (node == nil || node.Member() != ranks[to+1] || delta != to+1-from) {
+			t.Fatalf("seek after %v from rank %d: %v %d", v, from+1, node, delta)
+		}
+	}
+	var last = ranks[units-1]
+	if node, delta := zsl.SeekFrom(zsl.HeaderNode(), last.Populace+1, last); node != nil || delta != units {
+		t.Fatalf("seek after last: %v %d", node, delta)
+	}
+}
+
+func TestZSkipListUpdateNodeScore(t *testing.T) {
+	const units = 2000
+	var set = makeTestPlayers(units, 1000, true)
+	for _, opts := range [][]Option{nil, {WithDescending()}} {
+		var zsl = NewZSkipList(opts...)
+		var nodes = make(map[uint64]*ZSkipListNode, units)
+		for _, v := range set {
+			nodes[v.Uid] = zsl.Insert(v.Populace, v)
+		}
+		for i := 0; i < 5; i++ {
+			for _, v := range set {
+				var node = nodes[v.Uid]
+				var score = node.Score() + uint32(rand.Int()%21) - 10
+				if rand.Int()%10 == 0 {
+					score = uint32(rand.Int() % 1000)
+				}
+				if zsl.UpdateNodeScore(node, score) != node || node.Score() != score {
+					t.Fatalf("update %v to %d failed", v, score)
+				}
+				if zsl.GetRank(score, v) == 0 {
+					t.Fatalf("%v not found after update", v)
+				}
+			}
+		}
+		if err := zsl.Validate(); err != nil || zsl.Len() != units {
+			t.Fatalf("unexpected list after update, %d: %v", zsl.Len(), err)
+		}
+	}
+}
+
+func TestSkipListMoveDuplicate(t *testing.T) {
+	// members of the same last digit are the same element
+	var zsl = NewSkipList[int, int](func(a, b int) int {
+		return cmp.Compare(a%10, b%10)
+	}, WithDebug())
+	var nodes []*SkipListNode[int, int]
+	for i := 0; i < 1000; i++ {
+		nodes = append(nodes, zsl.Insert(i/10, i))
+	}
+	for i, x := range nodes {
+		// element (score, i%10) exists for any score of another node
+		var score = (i/10 + rand.Int()%20 - 10 + 100) % 100
+		if score == i/10 {
+			continue
+		}
+		if zsl.UpdateNodeScore(x, score) != nil {
+			t.Fatalf("move %d to duplicate %d should fail", i, score)
+		}
+		if err := zsl.Validate(); err != nil {
+			t.Fatalf("Validate: %v", err)
+		}
+		if x.Score() != i/10 || zsl.GetElementByRank(i+1) != x {
+			t.Fatalf("node %d is not restored", i)
+		}
+	}
+}
+
+func BenchmarkZSkipListUpdateNodeScore(b *testing.B) {
+	const units = 100000
+	var zsl, _ = makeSortedTestList(units, 10000)
+	var nodes = make([]*ZSkipListNode, 0, units)
+	for x := zsl.HeaderNode(); x != nil; x = x.Next() {
+		nodes = append(nodes, x)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var x = nodes[(i*7919)%units]
+		var score = x.Score() + uint32(rand.Int()%5)
+		zsl.UpdateNodeScore(x, score)
+	}
+}
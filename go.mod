@@ -0,0 +1,3 @@
+module github.com/yangmiok/go-zskiplist
+
+go 1.23
@@ -0,0 +1,152 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"cmp"
+	"iter"
+)
+
+// Iterator is a bidirectional cursor of list, it is positioned by First,
+// Last, Seek or SeekRank, then moved by Next and Prev. Rank of iterator
+// is the same as GetRank.
+// An iterator is invalidated by any modification of list, including score
+// update of the current element, which may move its node and leave the
+// rank stale, reposition it by Seek or SeekRank after modification.
+type Iterator[K comparable, S cmp.Ordered] struct {
+	zsl  *SkipList[K, S]
+	node *SkipListNode[K, S] // current node, nil if not valid
+	rank int                 // rank of current node
+}
+
+// Iterator return an iterator before the first element, so the first Next
+// moves it to the first element, use Last to iterate backward.
+func (zsl *SkipList[K, S]) Iterator() *Iterator[K, S] {
+	return &Iterator[K, S]{zsl: zsl}
+}
+
+// Valid return true if iterator is positioned at an element
+func (it *Iterator[K, S]) Valid() bool {
+	return it.node != nil
+}
+
+// First move to the first element
+func (it *Iterator[K, S]) First() bool {
+	it.node, it.rank = it.zsl.head.level[0].forward, 1
+	return it.node != nil
+}
+
+// Last move to the last element
+func (it *Iterator[K, S]) Last() bool {
+	it.node, it.rank = it.zsl.tail, it.zsl.length
+	return it.node != nil
+}
+
+// Seek move to the first element that is not before (score, obj) in
+// list order.
+func (it *Iterator[K, S]) Seek(score S, obj K) bool {
+	var rank = 0
+	var x = it.zsl.head
+	for i := it.zsl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil &&
+			it.zsl.compareTo(x.level[i].forward, score, obj) < 0 {
+			rank += x.level[i].span
+			x = x.level[i].forward
+		}
+	}
+	it.node, it.rank = x.level[0].forward, rank+1
+	return it.node != nil
+}
+
+// SeekRank move to the element at 1-based `rank`
+func (it *Iterator[K, S]) SeekRank(rank int) bool {
+	it.node, it.rank = nil, 0
+	if rank >= 1 {
+		it.node, it.rank = it.zsl.GetElementByRank(rank), rank
+	}
+	return it.node != nil
+}
+
+// Next move to the next element
+func (it *Iterator[K, S]) Next() bool {
+	if it.node == nil {
+		if it.rank <= 0 { // before the first
+			return it.First()
+		}
+		return false
+	}
+	it.node = it.node.level[0].forward
+	it.rank++
+	return it.node != nil
+}
+
+// Prev move to the previous element
+func (it *Iterator[K, S]) Prev() bool {
+	if it.node == nil {
+		if it.rank > it.zsl.length { // after the last
+			return it.Last()
+		}
+		return false
+	}
+	it.node = it.node.backward
+	it.rank--
+	return it.node != nil
+}
+
+// Rank return 1-based rank of current element
+func (it *Iterator[K, S]) Rank() int {
+	return it.rank
+}
+
+// Score return score of current element
+func (it *Iterator[K, S]) Score() S {
+	return it.node.score
+}
+
+// Member return member of current element
+func (it *Iterator[K, S]) Member() K {
+	return it.node.obj
+}
+
+// Node return current node, nil if iterator is not valid
+func (it *Iterator[K, S]) Node() *SkipListNode[K, S] {
+	return it.node
+}
+
+// All return an iterator over (member, score) of list in list order
+func (zsl *SkipList[K, S]) All() iter.Seq2[K, S] {
+	return func(yield func(K, S) bool) {
+		for x := zsl.head.level[0].forward; x != nil; x = x.level[0].forward {
+			if !yield(x.obj, x.score) {
+				return
+			}
+		}
+	}
+}
+
+// Backward return an iterator over (member, score) of list in reverse
+// list order
+func (zsl *SkipList[K, S]) Backward() iter.Seq2[K, S] {
+	return func(yield func(K, S) bool) {
+		for x := zsl.tail; x != nil; x = x.backward {
+			if !yield(x.obj, x.score) {
+				return
+			}
+		}
+	}
+}
+
+// ScoreRange return an iterator over (member, score) of elements in score
+// range in list order, the first element is located in O(log N).
+func (zsl *SkipList[K, S]) ScoreRange(r RangeSpec[S]) iter.Seq2[K, S] {
+	return func(yield func(K, S) bool) {
+		var x, _ = zsl.firstInRange(&r)
+		for ; x != nil && !zsl.afterRange(&r, x.score); x = x.level[0].forward {
+			if !yield(x.obj, x.score) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"testing"
+)
+
+func TestZSkipListIterator(t *testing.T) {
+	const units = 1000
+	var zsl, ranks = makeSortedTestList(units, 100)
+
+	var it = zsl.Iterator()
+	var count = 0
+	for it.Next() {
+		if it.Member() != ranks[count] || it.Score() != ranks[count].Populace || it.Rank() != count+1 {
+			t.Fatalf("forward item %d mismatch", count)
+		}
+		count++
+	}
+	if count != units {
+		t.Fatalf("forward iterated %d != %d", count, units)
+	}
+	for it.Prev() {
+		count--
+		if it.Member() != ranks[count] || it.Rank() != count+1 {
+			t.Fatalf("backward item %d mismatch", count)
+		}
+	}
+	if count != 0 || it.Valid() {
+		t.Fatalf("backward stopped at %d", count)
+	}
+
+	for i := 0; i < units; i += 37 {
+		var v = ranks[i]
+		if !it.Seek(v.Populace, v) || it.Member() != v || it.Rank() != i+1 {
+			t.Fatalf("seek %v mismatch", v)
+		}
+		if !it.SeekRank(i+1) || it.Member() != v {
+			t.Fatalf("seek rank %d mismatch", i+1)
+		}
+		// seek a missing member positions at the next element
+		if i > 0 && ranks[i-1].Populace == v.Populace && ranks[i-1].Uid == v.Uid-1 {
+			continue
+		}
+		if it.Seek(v.Populace, &testPlayer{Uid: v.Uid - 1}); it.Member() != v {
+			t.Fatalf("seek before %v mismatch", v)
+		}
+	}
+	var last = ranks[units-1]
+	if it.Seek(last.Populace+1, last) || !it.Prev() || it.Member() != last || it.Rank() != units {
+		t.Fatalf("seek after last mismatch")
+	}
+
+	count = 0
+	for obj, score := range zsl.All() {
+		if obj != ranks[count] || score != ranks[count].Populace {
+			t.Fatalf("All item %d mismatch", count)
+		}
+		count++
+	}
+	for obj := range zsl.Backward() {
+		count--
+		if obj != ranks[count] {
+			t.Fatalf("Backward item %d mismatch", count)
+		}
+	}
+	var r = RangeSpec[uint32]{Min: 20, Max: 30, MinEx: true}
+	var expected = filterRange(ranks, r)
+	count = 0
+	for obj := range zsl.ScoreRange(r) {
+		if obj != expected[count] {
+			t.Fatalf("ScoreRange item %d mismatch", count)
+		}
+		count++
+		if count == 5 {
+			break
+		}
+	}
+}
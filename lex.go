@@ -0,0 +1,284 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+)
+
+// A type that satisfies LexInterface can be ranked in a lex ZSkipList,
+// objects of same score are ordered by their LexKey().
+type LexInterface interface {
+	RankInterface
+
+	// Byte-string key compared lexicographically, e.g. name of player,
+	// it must not be changed while the object is in list
+	LexKey() []byte
+}
+
+// NewLexSkipList create a list whose members of same score are ordered by
+// `key` lexicographically like bytes.Compare, two members with the same
+// key are the same element. Lex range functions like RangeByLex can be used
+// on it, they return meaningful results only if all scores are equal.
+func NewLexSkipList[K comparable, S cmp.Ordered](key func(K) []byte, opts ...Option) *SkipList[K, S] {
+	var zsl = NewSkipList[K, S](func(a, b K) int {
+		return bytes.Compare(key(a), key(b))
+	}, opts...)
+	zsl.lexKey = key
+	return zsl
+}
+
+// NewZSkipListLex create a ZSkipList of LexInterface objects with tie-break
+// by CompareLex, so different objects may have the same key. Inserting an
+// object not satisfies LexInterface fails with ErrNotLexMember.
+func NewZSkipListLex(opts ...Option) *ZSkipList {
+	var zsl = NewSkipList[RankInterface, uint32](CompareLex, opts...)
+	zsl.lexKey = lexKeyOf
+	zsl.accept = acceptLex
+	return zsl
+}
+
+// CompareLex order LexInterface objects by LexKey() and then Uuid() ascending,
+// objects not satisfy LexInterface have an empty key.
+func CompareLex(a, b RankInterface) int {
+	if c := bytes.Compare(lexKeyOf(a), lexKeyOf(b)); c != 0 {
+		return c
+	}
+	return CompareUuid(a, b)
+}
+
+func lexKeyOf(obj RankInterface) []byte {
+	if v, ok := obj.(LexInterface); ok {
+		return v.LexKey()
+	}
+	return nil
+}
+
+// acceptLex reject objects not satisfy LexInterface
+func acceptLex(obj RankInterface) error {
+	if _, ok := obj.(LexInterface); !ok {
+		return fmt.Errorf("%w: %s", ErrNotLexMember, memberString(obj))
+	}
+	return nil
+}
+
+// lexBound is a bound of LexRangeSpec
+type lexBound struct {
+	key []byte
+	ex  bool // bound is exclusive
+	inf int  // -1 for "-" and +1 for "+", `key` is not used if not 0
+}
+
+// compareBound compare bound `a` with `b`
+func compareBound(a, b *lexBound) int {
+	if a.inf != 0 || b.inf != 0 {
+		return cmp.Compare(a.inf, b.inf)
+	}
+	return bytes.Compare(a.key, b.key)
+}
+
+// LexRangeSpec is a key range like zlexrangespec of redis, see ParseLexRange
+type LexRangeSpec struct {
+	min, max lexBound
+}
+
+// ParseLexRange parse `min` and `max` of a lex range like ZRANGEBYLEX,
+// "[key" is inclusive, "(key" is exclusive, "-" and "+" are the smallest
+// and the largest key. Returns an error wraps ErrInvalidLexRange if a bound
+// is not in these forms.
+func ParseLexRange(min, max string) (LexRangeSpec, error) {
+	var r LexRangeSpec
+	var err error
+	if r.min, err = parseLexBound(min); err != nil {
+		return r, err
+	}
+	if r.max, err = parseLexBound(max); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+func parseLexBound(s string) (lexBound, error) {
+	switch {
+	case s == "-":
+		return lexBound{inf: -1}, nil
+	case s == "+":
+		return lexBound{inf: 1}, nil
+	case len(s) > 0 && s[0] == '[':
+		return lexBound{key: []byte(s[1:])}, nil
+	case len(s) > 0 && s[0] == '(':
+		return lexBound{key: []byte(s[1:]), ex: true}, nil
+	}
+	return lexBound{}, fmt.Errorf("%w: %q", ErrInvalidLexRange, s)
+}
+
+func (r *LexRangeSpec) keyGteMin(key []byte) bool {
+	var c = compareBound(&r.min, &lexBound{key: key})
+	if r.min.ex {
+		return c < 0
+	}
+	return c <= 0
+}
+
+func (r *LexRangeSpec) keyLteMax(key []byte) bool {
+	var c = compareBound(&r.max, &lexBound{key: key})
+	if r.max.ex {
+		return c > 0
+	}
+	return c >= 0
+}
+
+// isEmpty test if no key can be in range
+func (r *LexRangeSpec) isEmpty() bool {
+	var c = compareBound(&r.min, &r.max)
+	return c > 0 || (c == 0 && (r.min.ex || r.max.ex))
+}
+
+// nodeKey return lex key of node `x`, it panics if list is not a lex list.
+func (zsl *SkipList[K, S]) nodeKey(x *SkipListNode[K, S]) []byte {
+	if zsl.lexKey == nil {
+		panic("zskiplist: lex range on a list without lex key")
+	}
+	return zsl.lexKey(x.obj)
+}
+
+// keys of equal score are in ascending order even in a descending list,
+// so lex ranges are not reversed like score ranges.
+
+// beforeLexRange test if node `x` is before range in list order
+func (zsl *SkipList[K, S]) beforeLexRange(r *LexRangeSpec, x *SkipListNode[K, S]) bool {
+	return !r.keyGteMin(zsl.nodeKey(x))
+}
+
+// afterLexRange test if node `x` is after range in list order
+func (zsl *SkipList[K, S]) afterLexRange(r *LexRangeSpec, x *SkipListNode[K, S]) bool {
+	return !r.keyLteMax(zsl.nodeKey(x))
+}
+
+// IsInLexRange Returns if there is a part of the list in lex range.
+func (zsl *SkipList[K, S]) IsInLexRange(r LexRangeSpec) bool {
+	if r.isEmpty() {
+		return false
+	}
+	var x = zsl.tail
+	if x == nil || zsl.beforeLexRange(&r, x) {
+		return false
+	}
+	x = zsl.head.level[0].forward
+	if x == nil || zsl.afterLexRange(&r, x) {
+		return false
+	}
+	return true
+}
+
+// firstInLexRange return the first node in lex range and its rank
+func (zsl *SkipList[K, S]) firstInLexRange(r *LexRangeSpec) (*SkipListNode[K, S], int) {
+	if !zsl.IsInLexRange(*r) {
+		return nil, 0
+	}
+	var rank = 0
+	var x = zsl.head
+	for i := zsl.level - 1; i >= 0; i-- {
+		// Go forward while *OUT* of range.
+		for x.level[i].forward != nil && zsl.beforeLexRange(r, x.level[i].forward) {
+			rank += x.level[i].span
+			x = x.level[i].forward
+		}
+	}
+	// This is an inner range, so the next node cannot be NULL.
+	x = x.level[0].forward
+	if zsl.afterLexRange(r, x) {
+		return nil, 0
+	}
+	return x, rank + 1
+}
+
+// lastInLexRange return the last node in lex range and its rank
+func (zsl *SkipList[K, S]) lastInLexRange(r *LexRangeSpec) (*SkipListNode[K, S], int) {
+	if !zsl.IsInLexRange(*r) {
+		return nil, 0
+	}
+	var rank = 0
+	var x = zsl.head
+	for i := zsl.level - 1; i >= 0; i-- {
+		// Go forward while *IN* range.
+		for x.level[i].forward != nil && !zsl.afterLexRange(r, x.level[i].forward) {
+			rank += x.level[i].span
+			x = x.level[i].forward
+		}
+	}
+	// This is an inner range, so this node cannot be NULL.
+	if x == zsl.head || zsl.beforeLexRange(r, x) {
+		return nil, 0
+	}
+	return x, rank
+}
+
+// CountByLex return # of elements in lex range, like ZLEXCOUNT
+func (zsl *SkipList[K, S]) CountByLex(r LexRangeSpec) int {
+	var _, first = zsl.firstInLexRange(&r)
+	if first == 0 {
+		return 0
+	}
+	var _, last = zsl.lastInLexRange(&r)
+	return last - first + 1
+}
+
+// RangeByLex return nodes in lex range by list order, like ZRANGEBYLEX,
+// skip `offset` nodes and return at most `limit` nodes, negative `limit`
+// means no limit.
+func (zsl *SkipList[K, S]) RangeByLex(r LexRangeSpec, offset, limit int) []*SkipListNode[K, S] {
+	var x, rank = zsl.firstInLexRange(&r)
+	if x == nil || offset < 0 || limit == 0 {
+		return nil
+	}
+	if offset > 0 {
+		x = zsl.GetElementByRank(rank + offset)
+	}
+	var nodes []*SkipListNode[K, S]
+	for x != nil && limit != 0 && !zsl.afterLexRange(&r, x) {
+		nodes = append(nodes, x)
+		limit--
+		x = x.level[0].forward
+	}
+	return nodes
+}
+
+// DeleteRangeByLex Delete all the elements in lex range from the skiplist,
+// like ZREMRANGEBYLEX, `fn` is called with each removed node if not nil.
+// Returns # of removed elements.
+func (zsl *SkipList[K, S]) DeleteRangeByLex(r LexRangeSpec, fn func(*SkipListNode[K, S])) int {
+	if r.isEmpty() {
+		return 0
+	}
+	var update [ZSKIPLIST_MAXLEVEL_LIMIT]*SkipListNode[K, S]
+	var x = zsl.head
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && zsl.beforeLexRange(&r, x.level[i].forward) {
+			x = x.level[i].forward
+		}
+		update[i] = x
+	}
+
+	// Current node is the last before range.
+	x = x.level[0].forward
+
+	// Delete nodes while in range.
+	var removed = 0
+	for x != nil && !zsl.afterLexRange(&r, x) {
+		var next = x.level[0].forward
+		zsl.deleteNode(x, update[0:])
+		zsl.logDelete(x.score, x.obj)
+		if fn != nil {
+			fn(x)
+		}
+		removed++
+		x = next
+	}
+	zsl.check()
+	return removed
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+type lexPlayer struct {
+	Uid  uint64
+	Name string
+}
+
+func (p *lexPlayer) Uuid() uint64 {
+	return p.Uid
+}
+
+func (p *lexPlayer) LexKey() []byte {
+	return []byte(p.Name)
+}
+
+func randLexBound(names []string) string {
+	switch n := rand.Int() % 10; {
+	case n == 0:
+		return "-"
+	case n == 1:
+		return "+"
+	}
+	var name = names[rand.Int()%len(names)]
+	if rand.Int()%3 == 0 {
+		name = name[:rand.Int()%(len(name)+1)]
+	}
+	if rand.Int()%2 == 0 {
+		return "[" + name
+	}
+	return "(" + name
+}
+
+// lexInRange test if `name` is in range of bounds `min` and `max`
+func lexInRange(name, min, max string) bool {
+	var gteMin, lteMax bool
+	switch min[0] {
+	case '-':
+		gteMin = true
+	case '[':
+		gteMin = name >= min[1:]
+	case '(':
+		gteMin = name > min[1:]
+	}
+	switch max[0] {
+	case '+':
+		lteMax = true
+	case '[':
+		lteMax = name <= max[1:]
+	case '(':
+		lteMax = name < max[1:]
+	}
+	return gteMin && lteMax
+}
+
+func TestSkipListRangeByLex(t *testing.T) {
+	var names []string
+	for i := 0; i < 500; i++ {
+		names = append(names, fmt.Sprintf("p%x", rand.Int()%4096))
+	}
+	var zsl = NewLexSkipList[string, int](func(s string) []byte {
+		return []byte(s)
+	}, WithDescending(), WithDebug())
+	for _, name := range names {
+		zsl.Insert(0, name)
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+	if zsl.Len() != len(names) {
+		t.Fatalf("unexpected element count, %d != %d", zsl.Len(), len(names))
+	}
+
+	for i := 0; i < 1000; i++ {
+		var lo, hi = randLexBound(names), randLexBound(names)
+		var r, err = ParseLexRange(lo, hi)
+		if err != nil {
+			t.Fatalf("ParseLexRange %s %s: %v", lo, hi, err)
+		}
+		var expected []string
+		for _, name := range names {
+			if lexInRange(name, lo, hi) {
+				expected = append(expected, name)
+			}
+		}
+		if n := zsl.CountByLex(r); n != len(expected) {
+			t.Fatalf("count by lex %s %s: %d != %d", lo, hi, n, len(expected))
+		}
+		var offset = rand.Int() % 5
+		var nodes = zsl.RangeByLex(r, offset, 10)
+		if offset >= len(expected) {
+			expected = nil
+		} else {
+			expected = expected[offset:min(offset+10, len(expected))]
+		}
+		if len(nodes) != len(expected) {
+			t.Fatalf("range by lex %s %s: %d != %d", lo, hi, len(nodes), len(expected))
+		}
+		for j, node := range nodes {
+			if node.Member() != expected[j] {
+				t.Fatalf("range by lex %s %s: %s != %s", lo, hi, node.Member(), expected[j])
+			}
+		}
+	}
+
+	var r, _ = ParseLexRange("[p1", "(p8")
+	var count = zsl.CountByLex(r)
+	var removed = 0
+	if n := zsl.DeleteRangeByLex(r, func(*SkipListNode[string, int]) { removed++ }); n != count || removed != count {
+		t.Fatalf("delete by lex: %d != %d", n, count)
+	}
+	if zsl.Len() != len(names)-count || zsl.CountByLex(r) != 0 {
+		t.Fatalf("unexpected element count after delete, %d", zsl.Len())
+	}
+
+	for _, bound := range []string{"", "p1", "*"} {
+		if _, err := ParseLexRange(bound, "+"); !errors.Is(err, ErrInvalidLexRange) {
+			t.Fatalf("ParseLexRange %q: %v", bound, err)
+		}
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("lex range on non-lex list should panic")
+			}
+		}()
+		var list = NewOrderedSkipList[string, int]()
+		list.Insert(0, "a")
+		list.CountByLex(r)
+	}()
+}
+
+func TestZSetLex(t *testing.T) {
+	var zs = NewZSetLex(WithDebug())
+	var names = []string{"alice", "bob", "bobby", "bob", "carol", "dave", "alice"}
+	for i, name := range names {
+		zs.Add(100, &lexPlayer{Uid: uint64(i + 1), Name: name})
+	}
+	var r, _ = ParseLexRange("[bob", "(carol")
+	var nodes = zs.List().RangeByLex(r, 0, -1)
+	var uuids []uint64
+	for _, node := range nodes {
+		uuids = append(uuids, node.Member().Uuid())
+	}
+	// same names are ordered by uuid
+	if !slices.Equal(uuids, []uint64{2, 4, 3}) {
+		t.Fatalf("unexpected range by lex %v", uuids)
+	}
+	if zs.Rank(7) != 2 || zs.Rank(5) != 6 {
+		t.Fatalf("unexpected rank %d %d", zs.Rank(7), zs.Rank(5))
+	}
+
+	// prefix search like autocomplete
+	r, _ = ParseLexRange("[b", "(c")
+	if n := zs.RemoveRangeByLex(r); n != 3 {
+		t.Fatalf("remove by lex: %d != 3", n)
+	}
+	if zs.Len() != 4 || zs.Contains(2) || zs.Contains(3) || !zs.Contains(5) {
+		t.Fatalf("unexpected set after remove by lex")
+	}
+
+	// objects without lex key are rejected rather than panic
+	var plain = &testPlayer{Uid: 10}
+	if zs.Add(100, plain) != nil || zs.Contains(10) || zs.Add(100, &testPlayer{Uid: 1}) != nil {
+		t.Fatalf("add object without lex key to lex set")
+	}
+	if _, err := zs.List().InsertE(100, plain); !errors.Is(err, ErrNotLexMember) {
+		t.Fatalf("insert object without lex key: %v", err)
+	}
+	var errs = zs.ApplyBatch([]Change[RankInterface, uint32]{{Op: ChangeInsert, Obj: plain, New: 1}})
+	if !errors.Is(errs[0], ErrNotLexMember) || zs.Len() != 4 {
+		t.Fatalf("batch insert object without lex key: %v", errs[0])
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+)
+
+// Log record layout, all integers are big endian:
+//
+//	size     uint32, size of payload
+//	checksum uint32, crc32 IEEE of payload
+//	payload  op uint8 followed by elements of op
+//
+// Elements are encoded as snapshot, an insert or delete record carries one
+// element, an update record carries the old and new element.
+const (
+	opInsert byte = 1 + iota
+	opDelete
+	opUpdate
+)
+
+const (
+	logHeaderSize    = 8
+	maxLogRecordSize = 1 << 24
+)
+
+// opLog is an append-only log of list operations
+type opLog struct {
+	w       io.Writer
+	buf     []byte       // frame of current record
+	scratch []byte       // scratch of member codec
+	err     error        // first error occurred, wraps ErrLog
+	logger  *slog.Logger // logger of the first error, nil to disable
+}
+
+// begin start a record with header reserved
+func (l *opLog) begin(op byte) []byte {
+	return append(l.buf[:0], 0, 0, 0, 0, 0, 0, 0, 0, op)
+}
+
+// commit fill header and write the record, return the first error of log.
+func (l *opLog) commit(buf []byte, err error) error {
+	if err == nil {
+		var payload = buf[logHeaderSize:]
+		binary.BigEndian.PutUint32(buf[0:], uint32(len(payload)))
+		binary.BigEndian.PutUint32(buf[4:], crc32.ChecksumIEEE(payload))
+		_, err = l.w.Write(buf)
+		l.buf = buf
+	}
+	if err != nil {
+		l.err = fmt.Errorf("%w: %w", ErrLog, err)
+		if l.logger != nil {
+			l.logger.Error("zskiplist: write log failed, no more record is written", "err", err)
+		}
+	}
+	return l.err
+}
+
+// AttachLog record every following Insert, Delete and score update of list
+// to `w`, a nil `w` detaches the log. Members are encoded the same as
+// snapshot, an error wraps ErrNoMemberCodec is returned and the log is not
+// attached if they cannot be, see SetMemberCodec.
+// Changes are applied to list before written to log, if writing failed, the
+// error wraps ErrLog and is returned by InsertE, DeleteE, UpdateScoreE and
+// other functions reporting errors, and no more record is written.
+func (zsl *SkipList[K, S]) AttachLog(w io.Writer) error {
+	if w == nil {
+		zsl.oplog = nil
+		return nil
+	}
+	if err := zsl.checkCodec(); err != nil {
+		return err
+	}
+	zsl.oplog = &opLog{w: w, logger: zsl.logger}
+	return nil
+}
+
+// LogErr return the first error of writing log, no more record is written
+// after an error occurred.
+func (zsl *SkipList[K, S]) LogErr() error {
+	if zsl.oplog == nil {
+		return nil
+	}
+	return zsl.oplog.err
+}
+
+// logInsert write an insert record, it and following log functions return
+// the first error of log.
+func (zsl *SkipList[K, S]) logInsert(score S, obj K) error {
+	var l = zsl.oplog
+	if l == nil || l.err != nil {
+		return zsl.LogErr()
+	}
+	var buf, err = zsl.appendElement(l.begin(opInsert), &l.scratch, score, obj)
+	return l.commit(buf, err)
+}
+
+func (zsl *SkipList[K, S]) logDelete(score S, obj K) error {
+	var l = zsl.oplog
+	if l == nil || l.err != nil {
+		return zsl.LogErr()
+	}
+	var buf, err = zsl.appendElement(l.begin(opDelete), &l.scratch, score, obj)
+	return l.commit(buf, err)
+}
+
+func (zsl *SkipList[K, S]) logUpdate(curScore S, obj K, newScore S, newObj K) error {
+	var l = zsl.oplog
+	if l == nil || l.err != nil {
+		return zsl.LogErr()
+	}
+	var buf, err = zsl.appendElement(l.begin(opUpdate), &l.scratch, curScore, obj)
+	if err == nil {
+		buf, err = zsl.appendElement(buf, &l.scratch, newScore, newObj)
+	}
+	return l.commit(buf, err)
+}
+
+// RewriteLog write the current elements of list to `w` as a new log and
+// attach it, like AOF rewrite of redis. The old log is kept attached if
+// error occurred.
+func (zsl *SkipList[K, S]) RewriteLog(w io.Writer) error {
+	if err := zsl.checkCodec(); err != nil {
+		return err
+	}
+	var oplog = zsl.oplog
+	zsl.oplog = &opLog{w: w, logger: zsl.logger}
+	for x := zsl.head.level[0].forward; x != nil; x = x.level[0].forward {
+		if err := zsl.logInsert(x.score, x.obj); err != nil {
+			zsl.oplog = oplog
+			return err
+		}
+	}
+	return nil
+}
+
+// Replay apply all records of log in `r` to list. Returns io.ErrUnexpectedEOF
+// if the last record is incomplete, e.g. crashed on writing, all records
+// before it are applied. Replayed records are not written to attached log.
+func (zsl *SkipList[K, S]) Replay(r io.Reader) error {
+	var oplog = zsl.oplog
+	zsl.oplog = nil
+	defer func() {
+		zsl.oplog = oplog
+	}()
+
+	var rd = bufio.NewReader(r)
+	var header [logHeaderSize]byte
+	var payload []byte
+	for {
+		if _, err := io.ReadFull(rd, header[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		var size = binary.BigEndian.Uint32(header[0:])
+		if size == 0 || size > maxLogRecordSize {
+			return fmt.Errorf("%w: bad record size %d", ErrInvalidLog, size)
+		}
+		if cap(payload) < int(size) {
+			payload = make([]byte, size)
+		}
+		payload = payload[:size]
+		if _, err := io.ReadFull(rd, payload); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:]) {
+			return ErrChecksum
+		}
+		if err := zsl.applyRecord(payload); err != nil {
+			return err
+		}
+	}
+}
+
+func (zsl *SkipList[K, S]) applyRecord(payload []byte) error {
+	var op, data = payload[0], payload[1:]
+	score, obj, n, err := zsl.readElement(data)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidLog, err)
+	}
+	data = data[n:]
+	switch op {
+	case opInsert:
+		if _, err = zsl.InsertE(score, obj); err != nil {
+			return fmt.Errorf("%w: insert %v: %w", ErrInvalidLog, obj, err)
+		}
+	case opDelete:
+		if _, err = zsl.DeleteE(score, obj); err != nil {
+			return fmt.Errorf("%w: delete %v: %w", ErrInvalidLog, obj, err)
+		}
+	case opUpdate:
+		newScore, newObj, m, err := zsl.readElement(data)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidLog, err)
+		}
+		data = data[m:]
+		if _, err = zsl.update(score, obj, newScore, newObj); err != nil {
+			return fmt.Errorf("%w: update %v: %w", ErrInvalidLog, obj, err)
+		}
+	default:
+		return fmt.Errorf("%w: unknown op %d", ErrInvalidLog, op)
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrInvalidLog, len(data))
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+func checkSameList(t *testing.T, a, b *ZSkipList) {
+	if a.Len() != b.Len() {
+		t.Fatalf("unexpected element count, %d != %d", b.Len(), a.Len())
+	}
+	for x, y := a.HeaderNode(), b.HeaderNode(); x != nil; x, y = x.Next(), y.Next() {
+		if x.Member().Uuid() != y.Member().Uuid() || x.Score() != y.Score() {
+			t.Fatalf("element mismatch, %d-%d != %d-%d", y.Member().Uuid(), y.Score(), x.Member().Uuid(), x.Score())
+		}
+	}
+}
+
+func TestZSkipListReplay(t *testing.T) {
+	const units = 5000
+	var set = makeTestPlayers(units, 1000, true)
+	var codec = UuidCodec(func(uuid uint64) (RankInterface, error) {
+		return set[uuid], nil
+	})
+	var wal bytes.Buffer
+	var zsl = NewZSkipList()
+	zsl.SetMemberCodec(codec)
+	if err := zsl.AttachLog(&wal); err != nil {
+		t.Fatalf("AttachLog: %v", err)
+	}
+	for _, v := range set {
+		zsl.Insert(v.Populace, v)
+	}
+	for _, v := range set {
+		var oldScore = v.Populace
+		v.Populace = uint32(rand.Int() % 1000)
+		zsl.UpdateScore(oldScore, v, v.Populace)
+	}
+	zsl.DeleteRangeByScore(RangeSpec[uint32]{Min: 100, Max: 200}, nil)
+	zsl.DeleteRangeByRank(1, 10, nil)
+	if node := zsl.GetElementByRank(1); node != nil {
+		zsl.Delete(node.Score(), node.Member())
+	}
+	if err := zsl.LogErr(); err != nil {
+		t.Fatalf("LogErr: %v", err)
+	}
+
+	var replayed = NewZSkipList()
+	replayed.SetMemberCodec(codec)
+	if err := replayed.Replay(bytes.NewReader(wal.Bytes())); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	checkSameList(t, zsl, replayed)
+
+	// rewrite and append more changes
+	var rewritten bytes.Buffer
+	if err := zsl.RewriteLog(&rewritten); err != nil {
+		t.Fatalf("RewriteLog: %v", err)
+	}
+	if rewritten.Len() >= wal.Len() {
+		t.Fatalf("rewritten log is not smaller, %d >= %d", rewritten.Len(), wal.Len())
+	}
+	var node = zsl.GetElementByRank(zsl.Len() / 2)
+	zsl.UpdateScore(node.Score(), node.Member(), node.Score()+5000)
+	replayed = NewZSkipList()
+	replayed.SetMemberCodec(codec)
+	if err := replayed.Replay(bytes.NewReader(rewritten.Bytes())); err != nil {
+		t.Fatalf("Replay rewritten: %v", err)
+	}
+	checkSameList(t, zsl, replayed)
+
+	// torn and corrupted tail
+	var data = rewritten.Bytes()
+	replayed = NewZSkipList()
+	replayed.SetMemberCodec(codec)
+	if err := replayed.Replay(bytes.NewReader(data[:len(data)-3])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("replay truncated log: %v", err)
+	}
+	data[len(data)-1] ^= 0xFF
+	replayed = NewZSkipList()
+	replayed.SetMemberCodec(codec)
+	if err := replayed.Replay(bytes.NewReader(data)); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("replay corrupted log: %v", err)
+	}
+	// only the last update record is lost
+	if replayed.Len() != zsl.Len() || replayed.GetRank(node.Score(), node.Member()) != 0 {
+		t.Fatalf("records before corrupted one should be applied")
+	}
+}
+
+// failWriter fails every write after `n` bytes written
+type failWriter struct {
+	n int
+}
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		return 0, io.ErrShortWrite
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestZSkipListLogErr(t *testing.T) {
+	var zsl = NewZSkipList(WithLogger(nil))
+	var wal bytes.Buffer
+	if err := zsl.AttachLog(&wal); !errors.Is(err, ErrNoMemberCodec) {
+		t.Fatalf("attach log without codec: %v", err)
+	}
+	if _, err := zsl.InsertE(1, &testPlayer{Uid: 1}); err != nil || wal.Len() != 0 {
+		t.Fatalf("insert without log: %v, %d bytes", err, wal.Len())
+	}
+	if err := NewOrderedSkipList[string, float64]().AttachLog(&wal); err != nil {
+		t.Fatalf("attach log of builtin members: %v", err)
+	}
+
+	zsl.SetMemberCodec(UuidCodec(func(uuid uint64) (RankInterface, error) {
+		return &testPlayer{Uid: uuid}, nil
+	}))
+	if err := zsl.AttachLog(&failWriter{n: 30}); err != nil {
+		t.Fatalf("AttachLog: %v", err)
+	}
+	var p2, p3 = &testPlayer{Uid: 2}, &testPlayer{Uid: 3}
+	if x, err := zsl.InsertE(2, p2); x == nil || err != nil {
+		t.Fatalf("insert with log: %v", err)
+	}
+	// a failed write is reported by the change and the following ones,
+	// which are still applied
+	if x, err := zsl.InsertE(3, p3); x == nil || !errors.Is(err, ErrLog) || zsl.Len() != 3 {
+		t.Fatalf("insert with failed log: %v", err)
+	}
+	if x, err := zsl.UpdateScoreE(3, p3, 4); x == nil || !errors.Is(err, ErrLog) || zsl.GetRank(4, p3) != 3 {
+		t.Fatalf("update with failed log: %v", err)
+	}
+	if _, err := zsl.UpdateScoreE(3, p3, 5); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("update missing element: %v", err)
+	}
+	if x, err := zsl.DeleteE(2, p2); x == nil || !errors.Is(err, ErrLog) || zsl.Len() != 2 {
+		t.Fatalf("delete with failed log: %v", err)
+	}
+	var errs = zsl.ApplyBatch([]Change[RankInterface, uint32]{{Op: ChangeInsert, Obj: p2, New: 2}})
+	if !errors.Is(errs[0], ErrLog) || zsl.GetRank(2, p2) == 0 {
+		t.Fatalf("batch with failed log: %v", errs[0])
+	}
+	if err := zsl.LogErr(); !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("LogErr: %v", err)
+	}
+	if err := zsl.RewriteLog(&wal); err != nil || zsl.LogErr() != nil {
+		t.Fatalf("RewriteLog: %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+)
+
+// options of a skiplist
+type options struct {
+	descending bool
+	logger     *slog.Logger
+	debug      bool
+	maxLevel   int
+	p          float64
+	rnd        *rand.Rand
+	uuidOrder  bool
+}
+
+// Option configures a skiplist on construction
+type Option func(*options)
+
+// WithDescending make the list in descend order of score, so the head is
+// the largest element and rank 1 is the highest score like a leaderboard.
+func WithDescending() Option {
+	return func(o *options) {
+		o.descending = true
+	}
+}
+
+// WithLogger set logger of warnings, e.g. Insert or Delete failed,
+// slog.Default() is used if not set, a nil logger disables logging.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithDebug validate list after every modification and panic if it is
+// corrupted, it makes every modification O(N) so only use it in tests.
+func WithDebug() Option {
+	return func(o *options) {
+		o.debug = true
+	}
+}
+
+// WithMaxLevel set max level of list, between 1 and ZSKIPLIST_MAXLEVEL_LIMIT.
+// A list works efficiently up to about (1/P)^maxLevel elements, e.g. 16 for
+// 4G elements with the default P. An element costs a node of 32 bytes plus
+// the member and score, and 24 bytes for each of its 1/(1-P) levels on
+// average on 64-bit platforms, e.g. about 88 bytes of ZSkipList with the
+// default P, a smaller P saves levels but makes search longer.
+func WithMaxLevel(maxLevel int) Option {
+	return func(o *options) {
+		o.maxLevel = maxLevel
+	}
+}
+
+// WithP set promotion probability of level, between 0 and 1 (exclusive).
+func WithP(p float64) Option {
+	return func(o *options) {
+		o.p = p
+	}
+}
+
+// WithRandSource set random source of level, which makes layout of list
+// deterministic with a seeded source. The global source of math/rand is
+// used if not set.
+func WithRandSource(src rand.Source) Option {
+	return func(o *options) {
+		o.rnd = rand.New(src)
+	}
+}
+
+// WithUuidOrder declare that the tie-break policy of a ZSkipList orders
+// members by Uuid() only, e.g. a policy wraps CompareUuidDesc, so a UuidKey
+// can stand in for a member and cursors need no member codec, see Cursor.
+// NewZSkipList and NewZSet imply it, other member types ignore it.
+func WithUuidOrder() Option {
+	return func(o *options) {
+		o.uuidOrder = true
+	}
+}
+
+// newOptions apply `opts` to default options, it panics on invalid options.
+func newOptions(opts []Option) options {
+	var o = options{
+		logger:   slog.Default(),
+		maxLevel: ZSKIPLIST_MAXLEVEL,
+		p:        ZSKIPLIST_P,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxLevel < 1 || o.maxLevel > ZSKIPLIST_MAXLEVEL_LIMIT {
+		panic(fmt.Sprintf("zskiplist: max level %d out of range [1, %d]", o.maxLevel, ZSKIPLIST_MAXLEVEL_LIMIT))
+	}
+	if !(o.p > 0 && o.p < 1) {
+		panic(fmt.Sprintf("zskiplist: P %v out of range (0, 1)", o.p))
+	}
+	return o
+}
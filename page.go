@@ -0,0 +1,160 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// cursor layout: version uint8 followed by an element encoded as snapshot,
+// in base64 url encoding without padding.
+const cursorVersion = 1
+
+// UuidKey is a RankInterface of a bare uuid, a UuidCodec may decode members
+// no longer in list to it, which is enough for lists ordered by CompareUuid.
+type UuidKey uint64
+
+func (k UuidKey) Uuid() uint64 {
+	return uint64(k)
+}
+
+// uuidKeyCodec is the default cursor codec of ZSkipList ordered by uuid
+var uuidKeyCodec = UuidCodec(func(uuid uint64) (RankInterface, error) {
+	return UuidKey(uuid), nil
+})
+
+// cursorCodec return codec of members in cursor. Without a member codec,
+// RankInterface members of a list ordered by uuid only, see WithUuidOrder,
+// are encoded by Uuid() and decoded to UuidKey. Other tie-break policies,
+// e.g. NewZSkipListLex, may not order a UuidKey, so their lists need a
+// codec. Unlike cursors, snapshot and log must restore the members, so
+// they always need a codec of RankInterface members.
+func (zsl *SkipList[K, S]) cursorCodec() (MemberCodec[K], error) {
+	if zsl.codec != nil {
+		return zsl.codec, nil
+	}
+	var codec, ok = any(uuidKeyCodec).(MemberCodec[K])
+	if !ok {
+		return nil, nil // builtin members, or ErrNoMemberCodec on encoding
+	}
+	if !zsl.uuidOrder {
+		return nil, fmt.Errorf("%w for custom tie-break policy", ErrNoMemberCodec)
+	}
+	return codec, nil
+}
+
+// Cursor return an opaque pagination token of element (score, obj),
+// members are encoded the same as snapshot, see SetMemberCodec. Members of
+// a ZSkipList ordered by uuid only, e.g. NewZSkipList, are encoded by Uuid()
+// if no codec is set, see cursorCodec.
+func (zsl *SkipList[K, S]) Cursor(score S, obj K) (string, error) {
+	var codec, err = zsl.cursorCodec()
+	if err != nil {
+		return "", err
+	}
+	var scratch []byte
+	buf, err := encodeElement(codec, []byte{cursorVersion}, &scratch, score, obj)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func (zsl *SkipList[K, S]) parseCursor(cursor string) (S, K, error) {
+	var score S
+	var obj K
+	var data, err = base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(data) == 0 || data[0] != cursorVersion {
+		return score, obj, ErrInvalidCursor
+	}
+	codec, err := zsl.cursorCodec()
+	if err != nil {
+		return score, obj, err
+	}
+	score, obj, n, err := decodeElement[K, S](codec, data[1:])
+	if err != nil {
+		return score, obj, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if n != len(data)-1 {
+		return score, obj, ErrInvalidCursor
+	}
+	return score, obj, nil
+}
+
+// PageAfter return at most `limit` nodes after the element of `cursor` in
+// list order, and the cursor of the last returned node. An empty `cursor`
+// starts from the first element. Since the cursor is the last element seen
+// rather than an offset, no element is skipped or repeated when others are
+// inserted or deleted between pages, and the element of cursor itself need
+// not exist any more. If no node is returned, the cursor is returned as is.
+// A ZSkipList with a custom tie-break policy needs a member codec that
+// decodes members the policy can order, ErrNoMemberCodec is returned
+// otherwise, see Cursor.
+func (zsl *SkipList[K, S]) PageAfter(cursor string, limit int) ([]*SkipListNode[K, S], string, error) {
+	var x = zsl.head.level[0].forward
+	if cursor != "" {
+		var score, obj, err = zsl.parseCursor(cursor)
+		if err != nil {
+			return nil, cursor, err
+		}
+		x = zsl.head
+		for i := zsl.level - 1; i >= 0; i-- {
+			for x.level[i].forward != nil &&
+				zsl.compareTo(x.level[i].forward, score, obj) <= 0 {
+				x = x.level[i].forward
+			}
+		}
+		x = x.level[0].forward
+	}
+	var nodes []*SkipListNode[K, S]
+	for ; x != nil && len(nodes) < limit; x = x.level[0].forward {
+		nodes = append(nodes, x)
+	}
+	return zsl.pageResult(nodes, cursor, len(nodes)-1)
+}
+
+// PageBefore return at most `limit` nodes before the element of `cursor`
+// in list order, and the cursor of the first returned node. An empty
+// `cursor` starts from the last element. Returned nodes are in list order.
+func (zsl *SkipList[K, S]) PageBefore(cursor string, limit int) ([]*SkipListNode[K, S], string, error) {
+	var x = zsl.tail
+	if cursor != "" {
+		var score, obj, err = zsl.parseCursor(cursor)
+		if err != nil {
+			return nil, cursor, err
+		}
+		x = zsl.head
+		for i := zsl.level - 1; i >= 0; i-- {
+			for x.level[i].forward != nil &&
+				zsl.compareTo(x.level[i].forward, score, obj) < 0 {
+				x = x.level[i].forward
+			}
+		}
+		if x == zsl.head {
+			x = nil
+		}
+	}
+	var nodes []*SkipListNode[K, S]
+	for ; x != nil && len(nodes) < limit; x = x.backward {
+		nodes = append(nodes, x)
+	}
+	for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+	return zsl.pageResult(nodes, cursor, 0)
+}
+
+// pageResult return `nodes` with cursor of nodes[i]
+func (zsl *SkipList[K, S]) pageResult(nodes []*SkipListNode[K, S], cursor string, i int) ([]*SkipListNode[K, S], string, error) {
+	if len(nodes) == 0 {
+		return nil, cursor, nil
+	}
+	var next, err = zsl.Cursor(nodes[i].score, nodes[i].obj)
+	if err != nil {
+		return nil, cursor, err
+	}
+	return nodes, next, nil
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestZSkipListPage(t *testing.T) {
+	const units = 1000
+	var zsl, ranks = makeSortedTestList(units, 100)
+	zsl.SetMemberCodec(UuidCodec(func(uuid uint64) (RankInterface, error) {
+		return UuidKey(uuid), nil
+	}))
+
+	// elements never changed should be seen exactly once
+	var stable = make(map[uint64]bool, units)
+	for _, v := range ranks {
+		stable[v.Uid] = true
+	}
+	var seen = make(map[uint64]bool, units)
+	var cursor string
+	var nextID uint64 = 1
+	for {
+		var nodes, next, err = zsl.PageAfter(cursor, 17)
+		if err != nil {
+			t.Fatalf("PageAfter: %v", err)
+		}
+		if len(nodes) == 0 {
+			if next != cursor {
+				t.Fatalf("cursor changed on empty page")
+			}
+			break
+		}
+		for _, node := range nodes {
+			var uuid = node.Member().Uuid()
+			if seen[uuid] {
+				t.Fatalf("element %d seen twice", uuid)
+			}
+			seen[uuid] = true
+		}
+		cursor = next
+
+		// insert and delete elements between pages
+		for i := 0; i < 5; i++ {
+			var p = &testPlayer{Uid: nextID, Populace: uint32(rand.Int() % 100)}
+			nextID++
+			zsl.Insert(p.Populace, p)
+		}
+		var last = nodes[len(nodes)-1]
+		if last.Member().Uuid() < nextID {
+			zsl.Delete(last.Score(), last.Member()) // cursor element itself is gone
+		} else {
+			var v = ranks[rand.Int()%units]
+			if stable[v.Uid] && zsl.Delete(v.Populace, v) != nil {
+				delete(stable, v.Uid)
+			}
+		}
+	}
+	for uuid := range stable {
+		if !seen[uuid] {
+			t.Fatalf("element %d skipped", uuid)
+		}
+	}
+
+	// page backward from the end
+	var total = 0
+	var prevFirst *ZSkipListNode
+	cursor = ""
+	for {
+		var nodes, prev, err = zsl.PageBefore(cursor, 23)
+		if err != nil {
+			t.Fatalf("PageBefore: %v", err)
+		}
+		if len(nodes) == 0 {
+			break
+		}
+		if prevFirst != nil && nodes[len(nodes)-1].Next() != prevFirst {
+			t.Fatalf("backward pages are not adjacent")
+		}
+		prevFirst = nodes[0]
+		total += len(nodes)
+		cursor = prev
+	}
+	if total != zsl.Len() {
+		t.Fatalf("backward paged %d != %d", total, zsl.Len())
+	}
+
+	if _, _, err := zsl.PageAfter("!bad", 10); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("PageAfter bad cursor: %v", err)
+	}
+}
+
+func TestZSkipListPageDefaultCodec(t *testing.T) {
+	var zsl = NewZSkipList(WithDescending())
+	for _, v := range makeTestPlayers(100, 20, true) {
+		zsl.Insert(v.Populace, v)
+	}
+	var total = 0
+	var cursor = ""
+	for {
+		var nodes, next, err = zsl.PageAfter(cursor, 30)
+		if err != nil {
+			t.Fatalf("PageAfter without codec: %v", err)
+		}
+		if len(nodes) == 0 {
+			break
+		}
+		if total > 0 && zsl.GetRank(nodes[0].Score(), nodes[0].Member()) != total+1 {
+			t.Fatalf("page after %s skipped elements", cursor)
+		}
+		total += len(nodes)
+		cursor = next
+	}
+	if total != zsl.Len() {
+		t.Fatalf("paged %d != %d", total, zsl.Len())
+	}
+
+	var custom = NewZSkipListFunc(func(a, b RankInterface) int {
+		return CompareUuid(a, b)
+	}, WithUuidOrder())
+	custom.Insert(1, &testPlayer{Uid: 1})
+	if _, err := custom.Cursor(1, &testPlayer{Uid: 1}); err != nil {
+		t.Fatalf("Cursor of uuid order: %v", err)
+	}
+	if _, err := NewZSkipListFunc(CompareUuidDesc).Cursor(1, &testPlayer{Uid: 1}); !errors.Is(err, ErrNoMemberCodec) {
+		t.Fatalf("Cursor of undeclared uuid order: %v", err)
+	}
+	if _, err := NewZSet().List().Cursor(1, &testPlayer{Uid: 1}); err != nil {
+		t.Fatalf("Cursor of ZSet: %v", err)
+	}
+	var lex = NewZSkipListLex()
+	lex.Insert(1, &lexPlayer{Uid: 1, Name: "a"})
+	if _, _, err := lex.PageAfter("", 10); !errors.Is(err, ErrNoMemberCodec) {
+		t.Fatalf("PageAfter of custom tie-break policy: %v", err)
+	}
+}
@@ -0,0 +1,362 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"cmp"
+)
+
+// RangeSpec is a score range like zrangespec of redis,
+// `MinEx` and `MaxEx` mean the bound is exclusive.
+type RangeSpec[S cmp.Ordered] struct {
+	Min, Max     S
+	MinEx, MaxEx bool
+}
+
+func (r *RangeSpec[S]) valueGteMin(value S) bool {
+	if r.MinEx {
+		return value > r.Min
+	}
+	return value >= r.Min
+}
+
+func (r *RangeSpec[S]) valueLteMax(value S) bool {
+	if r.MaxEx {
+		return value < r.Max
+	}
+	return value <= r.Max
+}
+
+// isEmpty test if no score can be in range
+func (r *RangeSpec[S]) isEmpty() bool {
+	return r.Min > r.Max || (r.Min == r.Max && (r.MinEx || r.MaxEx))
+}
+
+// beforeRange test if `score` is before range in list order
+func (zsl *SkipList[K, S]) beforeRange(r *RangeSpec[S], score S) bool {
+	if zsl.descending {
+		return !r.valueLteMax(score)
+	}
+	return !r.valueGteMin(score)
+}
+
+// afterRange test if `score` is after range in list order
+func (zsl *SkipList[K, S]) afterRange(r *RangeSpec[S], score S) bool {
+	if zsl.descending {
+		return !r.valueGteMin(score)
+	}
+	return !r.valueLteMax(score)
+}
+
+// IsInRange Returns if there is a part of the list in range.
+func (zsl *SkipList[K, S]) IsInRange(r RangeSpec[S]) bool {
+	if r.isEmpty() {
+		return false
+	}
+	var x = zsl.tail
+	if x == nil || zsl.beforeRange(&r, x.score) {
+		return false
+	}
+	x = zsl.head.level[0].forward
+	if x == nil || zsl.afterRange(&r, x.score) {
+		return false
+	}
+	return true
+}
+
+// firstInRange return the first node in range and its rank
+func (zsl *SkipList[K, S]) firstInRange(r *RangeSpec[S]) (*SkipListNode[K, S], int) {
+	if !zsl.IsInRange(*r) {
+		return nil, 0
+	}
+	var rank = 0
+	var x = zsl.head
+	for i := zsl.level - 1; i >= 0; i-- {
+		// Go forward while *OUT* of range.
+		for x.level[i].forward != nil && zsl.beforeRange(r, x.level[i].forward.score) {
+			rank += x.level[i].span
+			x = x.level[i].forward
+		}
+	}
+	// This is an inner range, so the next node cannot be NULL.
+	x = x.level[0].forward
+	if zsl.afterRange(r, x.score) {
+		return nil, 0
+	}
+	return x, rank + 1
+}
+
+// lastInRange return the last node in range and its rank
+func (zsl *SkipList[K, S]) lastInRange(r *RangeSpec[S]) (*SkipListNode[K, S], int) {
+	if !zsl.IsInRange(*r) {
+		return nil, 0
+	}
+	var rank = 0
+	var x = zsl.head
+	for i := zsl.level - 1; i >= 0; i-- {
+		// Go forward while *IN* range.
+		for x.level[i].forward != nil && !zsl.afterRange(r, x.level[i].forward.score) {
+			rank += x.level[i].span
+			x = x.level[i].forward
+		}
+	}
+	// This is an inner range, so this node cannot be NULL.
+	if x == zsl.head || zsl.beforeRange(r, x.score) {
+		return nil, 0
+	}
+	return x, rank
+}
+
+// FirstInRange Find the first node that is contained in the specified range.
+// Returns nil when no element is contained in the range.
+func (zsl *SkipList[K, S]) FirstInRange(r RangeSpec[S]) *SkipListNode[K, S] {
+	var x, _ = zsl.firstInRange(&r)
+	return x
+}
+
+// LastInRange Find the last node that is contained in the specified range.
+// Returns nil when no element is contained in the range.
+func (zsl *SkipList[K, S]) LastInRange(r RangeSpec[S]) *SkipListNode[K, S] {
+	var x, _ = zsl.lastInRange(&r)
+	return x
+}
+
+// CountInRange return # of elements in range, like ZCOUNT
+func (zsl *SkipList[K, S]) CountInRange(r RangeSpec[S]) int {
+	var _, first = zsl.firstInRange(&r)
+	if first == 0 {
+		return 0
+	}
+	var _, last = zsl.lastInRange(&r)
+	return last - first + 1
+}
+
+// RangeByScore return nodes in range by list order, like ZRANGEBYSCORE,
+// skip `offset` nodes and return at most `limit` nodes, negative `limit`
+// means no limit.
+func (zsl *SkipList[K, S]) RangeByScore(r RangeSpec[S], offset, limit int) []*SkipListNode[K, S] {
+	var x, rank = zsl.firstInRange(&r)
+	if x == nil || offset < 0 || limit == 0 {
+		return nil
+	}
+	if offset > 0 {
+		x = zsl.GetElementByRank(rank + offset)
+	}
+	var nodes []*SkipListNode[K, S]
+	for x != nil && limit != 0 && !zsl.afterRange(&r, x.score) {
+		nodes = append(nodes, x)
+		limit--
+		x = x.level[0].forward
+	}
+	return nodes
+}
+
+// RevRangeByScore return nodes in range by reverse list order, like ZREVRANGEBYSCORE,
+// skip `offset` nodes and return at most `limit` nodes, negative `limit`
+// means no limit.
+func (zsl *SkipList[K, S]) RevRangeByScore(r RangeSpec[S], offset, limit int) []*SkipListNode[K, S] {
+	var x, rank = zsl.lastInRange(&r)
+	if x == nil || offset < 0 || limit == 0 {
+		return nil
+	}
+	if offset >= rank {
+		return nil
+	}
+	if offset > 0 {
+		x = zsl.GetElementByRank(rank - offset)
+	}
+	var nodes []*SkipListNode[K, S]
+	for x != nil && limit != 0 && !zsl.beforeRange(&r, x.score) {
+		nodes = append(nodes, x)
+		limit--
+		x = x.backward
+	}
+	return nodes
+}
+
+// DeleteRangeByScore Delete all the elements with score between min and max
+// from the skiplist, `fn` is called with each removed node if not nil.
+// Returns # of removed elements.
+func (zsl *SkipList[K, S]) DeleteRangeByScore(r RangeSpec[S], fn func(*SkipListNode[K, S])) int {
+	if r.isEmpty() {
+		return 0
+	}
+	var update [ZSKIPLIST_MAXLEVEL_LIMIT]*SkipListNode[K, S]
+	var x = zsl.head
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && zsl.beforeRange(&r, x.level[i].forward.score) {
+			x = x.level[i].forward
+		}
+		update[i] = x
+	}
+
+	// Current node is the last before range.
+	x = x.level[0].forward
+
+	// Delete nodes while in range.
+	var removed = 0
+	for x != nil && !zsl.afterRange(&r, x.score) {
+		var next = x.level[0].forward
+		zsl.deleteNode(x, update[0:])
+		zsl.logDelete(x.score, x.obj)
+		if fn != nil {
+			fn(x)
+		}
+		removed++
+		x = next
+	}
+	zsl.check()
+	return removed
+}
+
+// DeleteRangeByRank Delete all the elements with rank between start and end
+// from the skiplist, start and end are inclusive and 1-based.
+// `fn` is called with each removed node if not nil.
+// Returns # of removed elements.
+func (zsl *SkipList[K, S]) DeleteRangeByRank(start, end int, fn func(*SkipListNode[K, S])) int {
+	if start < 1 {
+		start = 1
+	}
+	var update [ZSKIPLIST_MAXLEVEL_LIMIT]*SkipListNode[K, S]
+	var traversed = 0
+	var x = zsl.head
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && (traversed+x.level[i].span) < start {
+			traversed += x.level[i].span
+			x = x.level[i].forward
+		}
+		update[i] = x
+	}
+
+	traversed++
+	x = x.level[0].forward
+	var removed = 0
+	for x != nil && traversed <= end {
+		var next = x.level[0].forward
+		zsl.deleteNode(x, update[0:])
+		zsl.logDelete(x.score, x.obj)
+		if fn != nil {
+			fn(x)
+		}
+		removed++
+		traversed++
+		x = next
+	}
+	zsl.check()
+	return removed
+}
+
+// PopMin remove and return at most `n` elements of the lowest scores,
+// lowest first like ZPOPMIN. They are taken from head of an ascending list
+// or tail of a descending list in O(n) plus the span updates of O(log N)
+// levels, without any search or comparison. Elements taken from tail are
+// in reverse list order, so are their ties.
+func (zsl *SkipList[K, S]) PopMin(n int) []*SkipListNode[K, S] {
+	return zsl.pop(n, zsl.descending)
+}
+
+// PopMax remove and return at most `n` elements of the highest scores,
+// highest first like ZPOPMAX, see PopMin.
+func (zsl *SkipList[K, S]) PopMax(n int) []*SkipListNode[K, S] {
+	return zsl.pop(n, !zsl.descending)
+}
+
+// pop remove at most `n` elements from head, or tail if `fromTail`,
+// return them starting from the end they are taken from.
+func (zsl *SkipList[K, S]) pop(n int, fromTail bool) []*SkipListNode[K, S] {
+	n = min(n, zsl.length)
+	if n <= 0 {
+		return nil
+	}
+	if fromTail {
+		return zsl.popTail(n)
+	}
+	var nodes = make([]*SkipListNode[K, S], 0, n)
+	// the search of rank 1 stops at head immediately
+	zsl.DeleteRangeByRank(1, n, func(x *SkipListNode[K, S]) {
+		nodes = append(nodes, x)
+	})
+	return nodes
+}
+
+// popTail remove the last `n` elements by backward links, `n` must be
+// between 1 and length. The last remaining node of every level is found
+// by walking back from the new tail, and becomes the end of its level.
+func (zsl *SkipList[K, S]) popTail(n int) []*SkipListNode[K, S] {
+	var nodes = make([]*SkipListNode[K, S], 0, n)
+	for x := zsl.tail; len(nodes) < n; x = x.backward {
+		nodes = append(nodes, x)
+	}
+	var length = zsl.length - n
+	var x, rank = zsl.head, 0
+	if last := nodes[n-1].backward; last != nil {
+		x, rank = last, length
+	}
+	zsl.tail = nodes[n-1].backward
+	for i := 0; i < zsl.level; i++ {
+		for len(x.level) <= i {
+			var prev = x.level[i-1].backward
+			rank -= prev.level[i-1].span
+			x = prev
+		}
+		x.level[i].forward = nil
+		x.level[i].span = length - rank
+	}
+	for zsl.level > 1 && zsl.head.level[zsl.level-1].forward == nil {
+		zsl.level--
+	}
+	zsl.length = length
+	for _, x := range nodes {
+		zsl.logDelete(x.score, x.obj)
+	}
+	zsl.check()
+	return nodes
+}
+
+// RangeByRank return nodes by 0-based index range [start, stop] like ZRANGE,
+// negative index counts from the end, -1 is the last element.
+// If `reverse` is true, index 0 is the last element in list order like ZREVRANGE.
+func (zsl *SkipList[K, S]) RangeByRank(start, stop int, reverse bool) []*SkipListNode[K, S] {
+	var length = zsl.length
+	if start < 0 {
+		start = length + start
+	}
+	if stop < 0 {
+		stop = length + stop
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > stop || start >= length {
+		return nil
+	}
+	if stop >= length {
+		stop = length - 1
+	}
+
+	// Check if starting point is trivial, before doing log(N) lookup.
+	var x *SkipListNode[K, S]
+	if reverse {
+		x = zsl.tail
+		if start > 0 {
+			x = zsl.GetElementByRank(length - start)
+		}
+	} else {
+		x = zsl.head.level[0].forward
+		if start > 0 {
+			x = zsl.GetElementByRank(start + 1)
+		}
+	}
+	var nodes = make([]*SkipListNode[K, S], 0, stop-start+1)
+	for i := start; i <= stop; i++ {
+		nodes = append(nodes, x)
+		if reverse {
+			x = x.backward
+		} else {
+			x = x.level[0].forward
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,320 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func makeSortedTestList(units, maxScore int) (*ZSkipList, []*testPlayer) {
+	var set = makeTestPlayers(units, maxScore, true)
+	var zsl = NewZSkipList()
+	for _, v := range set {
+		zsl.Insert(v.Populace, v)
+	}
+	var ranks = mapToSlice(set)
+	sort.Slice(ranks, func(i, j int) bool {
+		if ranks[i].Populace != ranks[j].Populace {
+			return ranks[i].Populace < ranks[j].Populace
+		}
+		return ranks[i].Uid < ranks[j].Uid
+	})
+	return zsl, ranks
+}
+
+func filterRange(ranks []*testPlayer, r RangeSpec[uint32]) []*testPlayer {
+	var result []*testPlayer
+	for _, v := range ranks {
+		if r.valueGteMin(v.Populace) && r.valueLteMax(v.Populace) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func TestZSkipListRangeByScore(t *testing.T) {
+	const units = 2000
+	var zsl, ranks = makeSortedTestList(units, 500)
+	for i := 0; i < 1000; i++ {
+		var r = RangeSpec[uint32]{
+			Min:   uint32(rand.Int() % 520),
+			Max:   uint32(rand.Int() % 520),
+			MinEx: rand.Int()%2 == 0,
+			MaxEx: rand.Int()%2 == 0,
+		}
+		var expected = filterRange(ranks, r)
+		if n := zsl.CountInRange(r); n != len(expected) {
+			t.Fatalf("count in range %+v: %d != %d", r, n, len(expected))
+		}
+		var first, last = zsl.FirstInRange(r), zsl.LastInRange(r)
+		if len(expected) == 0 {
+			if first != nil || last != nil {
+				t.Fatalf("range %+v expected empty", r)
+			}
+			continue
+		}
+		if first.Member() != expected[0] || last.Member() != expected[len(expected)-1] {
+			t.Fatalf("range %+v bounds mismatch", r)
+		}
+
+		var offset, limit = rand.Int() % 10, rand.Int()%20 - 1
+		var nodes = zsl.RangeByScore(r, offset, limit)
+		var want []*testPlayer
+		if offset < len(expected) {
+			want = expected[offset:]
+		}
+		if limit >= 0 && limit < len(want) {
+			want = want[:limit]
+		}
+		if len(nodes) != len(want) {
+			t.Fatalf("range %+v offset %d limit %d: %d != %d", r, offset, limit, len(nodes), len(want))
+		}
+		for j, node := range nodes {
+			if node.Member() != want[j] {
+				t.Fatalf("range %+v item %d mismatch", r, j)
+			}
+		}
+
+		nodes = zsl.RevRangeByScore(r, offset, limit)
+		want = nil
+		for j := len(expected) - 1 - offset; j >= 0; j-- {
+			if limit >= 0 && len(want) == limit {
+				break
+			}
+			want = append(want, expected[j])
+		}
+		if len(nodes) != len(want) {
+			t.Fatalf("reverse range %+v offset %d limit %d: %d != %d", r, offset, limit, len(nodes), len(want))
+		}
+		for j, node := range nodes {
+			if node.Member() != want[j] {
+				t.Fatalf("reverse range %+v item %d mismatch", r, j)
+			}
+		}
+	}
+}
+
+func TestZSkipListDeleteRange(t *testing.T) {
+	const units = 2000
+	for i := 0; i < 100; i++ {
+		var zsl, ranks = makeSortedTestList(units, 500)
+		var r = RangeSpec[uint32]{
+			Min:   uint32(rand.Int() % 520),
+			Max:   uint32(rand.Int() % 520),
+			MinEx: rand.Int()%2 == 0,
+			MaxEx: rand.Int()%2 == 0,
+		}
+		var expected = filterRange(ranks, r)
+		var removed []RankInterface
+		var n = zsl.DeleteRangeByScore(r, func(node *ZSkipListNode) {
+			removed = append(removed, node.Member())
+		})
+		if n != len(expected) || len(removed) != n {
+			t.Fatalf("delete range %+v: %d != %d", r, n, len(expected))
+		}
+		for j, v := range expected {
+			if removed[j] != v {
+				t.Fatalf("delete range %+v item %d mismatch", r, j)
+			}
+		}
+		if zsl.Len() != units-n || zsl.CountInRange(r) != 0 {
+			t.Fatalf("delete range %+v left %d items", r, zsl.Len())
+		}
+
+		if zsl.Len() == 0 {
+			continue // range covered all scores
+		}
+		var start, end = rand.Int()%zsl.Len() + 1, rand.Int()%zsl.Len() + 1
+		var length = zsl.Len()
+		var first, last = zsl.GetElementByRank(start - 1), zsl.GetElementByRank(end + 1)
+		n = zsl.DeleteRangeByRank(start, end, nil)
+		if start > end {
+			if n != 0 {
+				t.Fatalf("delete rank [%d, %d]: %d removed", start, end, n)
+			}
+			continue
+		}
+		if n != end-start+1 || zsl.Len() != length-n {
+			t.Fatalf("delete rank [%d, %d]: %d removed", start, end, n)
+		}
+		if start > 1 && zsl.GetElementByRank(start-1) != first {
+			t.Fatalf("delete rank [%d, %d]: item before range changed", start, end)
+		}
+		if last != nil && zsl.GetElementByRank(start) != last {
+			t.Fatalf("delete rank [%d, %d]: item after range mismatch", start, end)
+		}
+	}
+}
+
+func TestZSkipListRangeByRank(t *testing.T) {
+	const units = 500
+	var zsl, ranks = makeSortedTestList(units, 100)
+	for i := 0; i < 1000; i++ {
+		var start, stop = rand.Int()%(units*2) - units, rand.Int()%(units*2) - units
+		var reverse = rand.Int()%2 == 0
+		var nodes = zsl.RangeByRank(start, stop, reverse)
+
+		var from, to = start, stop
+		if from < 0 {
+			from += units
+		}
+		if to < 0 {
+			to += units
+		}
+		if from < 0 {
+			from = 0
+		}
+		if to >= units {
+			to = units - 1
+		}
+		var want []*testPlayer
+		for j := from; j <= to; j++ {
+			if reverse {
+				want = append(want, ranks[units-1-j])
+			} else {
+				want = append(want, ranks[j])
+			}
+		}
+		if len(nodes) != len(want) {
+			t.Fatalf("range [%d, %d] reverse %v: %d != %d", start, stop, reverse, len(nodes), len(want))
+		}
+		for j, node := range nodes {
+			if node.Member() != want[j] {
+				t.Fatalf("range [%d, %d] reverse %v item %d mismatch", start, stop, reverse, j)
+			}
+		}
+	}
+	for i, v := range ranks {
+		if rank := zsl.RevRank(v.Populace, v); rank != units-i {
+			t.Fatalf("reverse rank of %v: %d != %d", v, rank, units-i)
+		}
+	}
+}
+
+func TestZSkipListDescending(t *testing.T) {
+	const units = 2000
+	var set = makeTestPlayers(units, 500, true)
+	var zsl = NewZSkipList(WithDescending())
+	for _, v := range set {
+		zsl.Insert(v.Populace, v)
+	}
+	var ranks = mapToSlice(set)
+	sort.Slice(ranks, func(i, j int) bool {
+		if ranks[i].Populace != ranks[j].Populace {
+			return ranks[i].Populace > ranks[j].Populace
+		}
+		return ranks[i].Uid < ranks[j].Uid
+	})
+	for i, v := range ranks {
+		if rank := zsl.GetRank(v.Populace, v); rank != i+1 {
+			t.Fatalf("rank of %v: %d != %d", v, rank, i+1)
+		}
+		if node := zsl.GetElementByRank(i + 1); node.Member() != v {
+			t.Fatalf("element at rank %d: %v != %v", i+1, node.Member(), v)
+		}
+	}
+	if top := zsl.RangeByRank(0, 0, false); top[0].Member() != ranks[0] {
+		t.Fatalf("unexpected top element %v", top[0].Member())
+	}
+
+	for i := 0; i < 200; i++ {
+		var r = RangeSpec[uint32]{
+			Min:   uint32(rand.Int() % 520),
+			Max:   uint32(rand.Int() % 520),
+			MinEx: rand.Int()%2 == 0,
+			MaxEx: rand.Int()%2 == 0,
+		}
+		var expected = filterRange(ranks, r)
+		if n := zsl.CountInRange(r); n != len(expected) {
+			t.Fatalf("count in range %+v: %d != %d", r, n, len(expected))
+		}
+		var nodes = zsl.RangeByScore(r, 0, -1)
+		if len(nodes) != len(expected) {
+			t.Fatalf("range %+v: %d != %d", r, len(nodes), len(expected))
+		}
+		for j, node := range nodes {
+			if node.Member() != expected[j] {
+				t.Fatalf("range %+v item %d mismatch", r, j)
+			}
+		}
+		if len(expected) > 0 && zsl.RevRangeByScore(r, 0, 1)[0].Member() != expected[len(expected)-1] {
+			t.Fatalf("reverse range %+v first item mismatch", r)
+		}
+	}
+
+	var r = RangeSpec[uint32]{Min: 100, Max: 200}
+	var n = zsl.DeleteRangeByScore(r, nil)
+	if n != len(filterRange(ranks, r)) || zsl.CountInRange(r) != 0 || zsl.Len() != units-n {
+		t.Fatalf("delete range %+v: %d removed", r, n)
+	}
+}
+
+func TestSkipListPop(t *testing.T) {
+	for _, descending := range []bool{false, true} {
+		var opts = []Option{WithDebug()}
+		if descending {
+			opts = append(opts, WithDescending())
+		}
+		var zsl = NewOrderedSkipList[int, int](opts...)
+		var values = rand.Perm(1000)
+		for _, v := range values {
+			zsl.Insert(v, v)
+		}
+		sort.Ints(values)
+
+		var nodes = zsl.PopMin(10)
+		for i, node := range nodes {
+			if node.Member() != values[i] {
+				t.Fatalf("pop min %d: %d != %d", i, node.Member(), values[i])
+			}
+		}
+		nodes = zsl.PopMax(10)
+		for i, node := range nodes {
+			if node.Member() != values[len(values)-1-i] {
+				t.Fatalf("pop max %d: %d != %d", i, node.Member(), values[len(values)-1-i])
+			}
+		}
+		values = values[10 : len(values)-10]
+		if zsl.Len() != len(values) {
+			t.Fatalf("unexpected element count, %d != %d", zsl.Len(), len(values))
+		}
+		if x := zsl.PopMin(1)[0]; x.Member() != values[0] {
+			t.Fatalf("pop min: %d != %d", x.Member(), values[0])
+		}
+		if nodes = zsl.PopMax(zsl.Len() + 10); len(nodes) != len(values)-1 || zsl.Len() != 0 {
+			t.Fatalf("pop all: %d != %d", len(nodes), len(values)-1)
+		}
+		if zsl.PopMin(1) != nil || zsl.PopMax(0) != nil {
+			t.Fatalf("pop empty list")
+		}
+
+		// pop both ends in turn, the list is validated after every pop
+		for _, v := range values {
+			zsl.Insert(v, v)
+		}
+		for i := 0; zsl.Len() > 0; i++ {
+			var n = rand.Int()%3 + 1
+			var first, last = zsl.HeaderNode(), zsl.TailNode()
+			if i%2 == 0 {
+				if nodes = zsl.pop(n, true); nodes[0] != last {
+					t.Fatalf("pop tail: %d != %d", nodes[0].Member(), last.Member())
+				}
+			} else if nodes = zsl.pop(n, false); nodes[0] != first {
+				t.Fatalf("pop head: %d != %d", nodes[0].Member(), first.Member())
+			}
+		}
+	}
+
+	var zs = NewZSet(WithDescending())
+	for _, v := range makeTestPlayers(100, 50, true) {
+		zs.Add(v.Populace, v)
+	}
+	var top = zs.List().HeaderNode().Member()
+	if nodes := zs.PopMax(1); nodes[0].Member() != top || zs.Contains(top.Uuid()) || zs.Len() != 99 {
+		t.Fatalf("unexpected pop max of set")
+	}
+}
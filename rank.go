@@ -0,0 +1,191 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"fmt"
+	"math"
+)
+
+// GetRank gives distinct ordinal ranks "1, 2, 3, 4" to tied scores, the
+// functions here give the same rank to elements of the same score:
+// standard competition ranking "1, 2, 2, 4" and dense ranking "1, 2, 2, 3".
+
+// searchScore return the first node whose score is not before `score` in
+// list order, or after it if not `inclusive`, and its 1-based rank.
+// Returns nil and length+1 if there is no such node.
+func (zsl *SkipList[K, S]) searchScore(score S, inclusive bool) (*SkipListNode[K, S], int) {
+	var limit = 0
+	if !inclusive {
+		limit = 1
+	}
+	var rank = 0
+	var x = zsl.head
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && zsl.compareScore(x.level[i].forward.score, score) < limit {
+			rank += x.level[i].span
+			x = x.level[i].forward
+		}
+	}
+	return x.level[0].forward, rank + 1
+}
+
+// nextScore return the first node after `x` with a different score, it
+// goes forward by the highest link of each node staying in the same score,
+// so a long run of ties is skipped in O(log run) expected.
+func (zsl *SkipList[K, S]) nextScore(x *SkipListNode[K, S]) *SkipListNode[K, S] {
+	var score = x.score
+	for {
+		var i = len(x.level) - 1
+		for i > 0 && (x.level[i].forward == nil || x.level[i].forward.score != score) {
+			i--
+		}
+		var next = x.level[i].forward
+		if next == nil || next.score != score {
+			return next
+		}
+		x = next
+	}
+}
+
+// countDistinct return # of distinct scores of nodes from `x` to `end`
+// (exclusive), `end` must be nil or the first node of its score.
+func (zsl *SkipList[K, S]) countDistinct(x, end *SkipListNode[K, S]) int {
+	var count = 0
+	for ; x != end; x = zsl.nextScore(x) {
+		count++
+	}
+	return count
+}
+
+// CompetitionRank return the standard competition rank of `score` in
+// O(log N), which is 1 + # of elements before the first element of `score`
+// in list order, e.g. "1, 2, 2, 4". Returns 0 if no element has `score`.
+func (zsl *SkipList[K, S]) CompetitionRank(score S) int {
+	var x, rank = zsl.searchScore(score, true)
+	if x == nil || x.score != score {
+		return 0
+	}
+	return rank
+}
+
+// DenseRank return the dense rank of `score`, which is 1 + # of distinct
+// scores before it in list order, e.g. "1, 2, 2, 3". The first element of
+// `score` is located in O(log N), then runs of ties before it are skipped,
+// so it costs O(D log N) at most for D distinct scores before it.
+// Returns 0 if no element has `score`.
+func (zsl *SkipList[K, S]) DenseRank(score S) int {
+	var x, _ = zsl.searchScore(score, true)
+	if x == nil || x.score != score {
+		return 0
+	}
+	return zsl.countDistinct(zsl.head.level[0].forward, x) + 1
+}
+
+// CountDistinctAbove return # of distinct scores greater than `score` in
+// list, `score` need not be in list, see DenseRank for its cost. It is
+// DenseRank(score)-1 of a descending list.
+func (zsl *SkipList[K, S]) CountDistinctAbove(score S) int {
+	if zsl.descending {
+		var end, _ = zsl.searchScore(score, true)
+		return zsl.countDistinct(zsl.head.level[0].forward, end)
+	}
+	var x, _ = zsl.searchScore(score, false)
+	return zsl.countDistinct(x, nil)
+}
+
+// Quantiles below are in list order with nearest-rank method: quantile q
+// is the element at rank ceil(q*N) (at least 1), so in a descending list
+// Percentile < 0.01 means "top 1%". Ties share the percentile of their
+// first element, so an element is within quantile q if and only if its
+// score is not after ScoreAtQuantile(q) in list order, and all ties of the
+// boundary score are within. The Rev variants apply the same rule in
+// reverse list order, e.g. RevPercentile < 0.01 means "top 1%" of an
+// ascending list.
+
+// Percentile return the fraction in [0, 1) of elements before the score
+// of element (score, obj) in list order, which is (CompetitionRank-1)/N.
+// Returns -1 if the element is not found.
+func (zsl *SkipList[K, S]) Percentile(score S, obj K) float64 {
+	return zsl.percentile(score, obj, false)
+}
+
+// RevPercentile return the fraction in [0, 1) of elements after the score
+// of element (score, obj) in list order, see Percentile.
+func (zsl *SkipList[K, S]) RevPercentile(score S, obj K) float64 {
+	return zsl.percentile(score, obj, true)
+}
+
+func (zsl *SkipList[K, S]) percentile(score S, obj K, reverse bool) float64 {
+	if zsl.GetRank(score, obj) == 0 {
+		return -1
+	}
+	var before int
+	if reverse {
+		var _, rank = zsl.searchScore(score, false)
+		before = zsl.length - rank + 1
+	} else {
+		before = zsl.CompetitionRank(score) - 1
+	}
+	return float64(before) / float64(zsl.length)
+}
+
+// ScoreAtQuantile return the score of element at quantile `q` in list
+// order, false if list is empty. It panics if `q` is not in [0, 1].
+func (zsl *SkipList[K, S]) ScoreAtQuantile(q float64) (S, bool) {
+	return zsl.scoreAtQuantile(q, false)
+}
+
+// RevScoreAtQuantile return the score of element at quantile `q` in reverse
+// list order, see ScoreAtQuantile.
+func (zsl *SkipList[K, S]) RevScoreAtQuantile(q float64) (S, bool) {
+	return zsl.scoreAtQuantile(q, true)
+}
+
+func (zsl *SkipList[K, S]) scoreAtQuantile(q float64, reverse bool) (S, bool) {
+	var rank = zsl.quantileRank(q)
+	if rank == 0 {
+		var zero S
+		return zero, false
+	}
+	if reverse {
+		rank = zsl.length - rank + 1
+	}
+	return zsl.GetElementByRank(rank).score, true
+}
+
+// QuantileBoundaries return scores at each quantile of `qs`, e.g. tier
+// boundaries of {0.01, 0.1, 0.5}, see ScoreAtQuantile. Returns nil if list
+// is empty. It panics if a quantile is not in [0, 1].
+func (zsl *SkipList[K, S]) QuantileBoundaries(qs []float64) []S {
+	return zsl.quantileBoundaries(qs, false)
+}
+
+// RevQuantileBoundaries return scores at each quantile of `qs` in reverse
+// list order, see QuantileBoundaries.
+func (zsl *SkipList[K, S]) RevQuantileBoundaries(qs []float64) []S {
+	return zsl.quantileBoundaries(qs, true)
+}
+
+func (zsl *SkipList[K, S]) quantileBoundaries(qs []float64, reverse bool) []S {
+	if zsl.length == 0 {
+		return nil
+	}
+	var scores = make([]S, len(qs))
+	for i, q := range qs {
+		scores[i], _ = zsl.scoreAtQuantile(q, reverse)
+	}
+	return scores
+}
+
+// quantileRank return rank of element at quantile `q`, 0 if list is empty
+func (zsl *SkipList[K, S]) quantileRank(q float64) int {
+	if !(q >= 0 && q <= 1) {
+		panic(fmt.Sprintf("zskiplist: quantile %v out of range [0, 1]", q))
+	}
+	// tolerate rounding error of q*N, e.g. 0.07*100 is 7.000000000000001
+	var rank = int(math.Ceil(q*float64(zsl.length) - 1e-9))
+	return max(rank, min(1, zsl.length))
+}
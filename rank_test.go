@@ -0,0 +1,150 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"testing"
+)
+
+func TestZSkipListRankModes(t *testing.T) {
+	for _, descending := range []bool{false, true} {
+		var opts []Option
+		if descending {
+			opts = append(opts, WithDescending())
+		}
+		var zs = NewZSet(opts...)
+		var set = makeTestPlayers(2000, 300, true)
+		for _, v := range set {
+			zs.Add(v.Populace, v)
+		}
+		var zsl = zs.List()
+
+		// expected ranks by walking list
+		var competition = make(map[uint32]int)
+		var dense = make(map[uint32]int)
+		var rank = 0
+		for x := zsl.HeaderNode(); x != nil; x = x.Next() {
+			rank++
+			if _, found := competition[x.Score()]; !found {
+				competition[x.Score()] = rank
+				dense[x.Score()] = len(dense) + 1
+			}
+		}
+		for score := uint32(0); score <= 302; score++ {
+			if n := zsl.CompetitionRank(score); n != competition[score] {
+				t.Fatalf("competition rank of %d: %d != %d", score, n, competition[score])
+			}
+			if n := zsl.DenseRank(score); n != dense[score] {
+				t.Fatalf("dense rank of %d: %d != %d", score, n, dense[score])
+			}
+			var above = 0
+			for s := range dense {
+				if s > score {
+					above++
+				}
+			}
+			if n := zsl.CountDistinctAbove(score); n != above {
+				t.Fatalf("distinct scores above %d: %d != %d", score, n, above)
+			}
+		}
+		for _, v := range set {
+			if zs.CompetitionRank(v.Uid) != competition[v.Populace] || zs.DenseRank(v.Uid) != dense[v.Populace] {
+				t.Fatalf("unexpected ranks of %d", v.Uid)
+			}
+		}
+		if zs.CompetitionRank(1) != 0 || zs.DenseRank(1) != 0 {
+			t.Fatalf("unexpected ranks of missing object")
+		}
+	}
+
+	var zsl = NewOrderedSkipList[string, int](WithDescending())
+	for i, score := range []int{90, 80, 80, 70} {
+		zsl.Insert(score, string(rune('a'+i)))
+	}
+	for i, expected := range [][2]int{{1, 1}, {2, 2}, {2, 2}, {4, 3}} {
+		var score = zsl.GetElementByRank(i + 1).Score()
+		if zsl.CompetitionRank(score) != expected[0] || zsl.DenseRank(score) != expected[1] {
+			t.Fatalf("ranks of %d: %d %d", score, zsl.CompetitionRank(score), zsl.DenseRank(score))
+		}
+	}
+}
+
+func TestZSkipListQuantile(t *testing.T) {
+	for _, descending := range []bool{false, true} {
+		var opts []Option
+		if descending {
+			opts = append(opts, WithDescending())
+		}
+		var zs = NewZSet(opts...)
+		var set = makeTestPlayers(1000, 200, true)
+		for _, v := range set {
+			zs.Add(v.Populace, v)
+		}
+		var zsl = zs.List()
+		var qs = []float64{0, 0.01, 0.07, 0.1, 0.5, 0.999, 1}
+		var ranks = []int{1, 10, 70, 100, 500, 999, 1000}
+		var boundaries = zsl.QuantileBoundaries(qs)
+		for i, q := range qs {
+			var expected = zsl.GetElementByRank(ranks[i]).Score()
+			if score, ok := zsl.ScoreAtQuantile(q); !ok || score != expected || boundaries[i] != expected {
+				t.Fatalf("score at quantile %v: %d %d != %d", q, score, boundaries[i], expected)
+			}
+			if q == 0 {
+				continue
+			}
+			// an element is within quantile q iff its score is not after the boundary
+			for _, v := range set {
+				var p = zs.Percentile(v.Uid)
+				if (p < q) != (zsl.compareScore(v.Populace, expected) <= 0) {
+					t.Fatalf("percentile %v of %d, quantile %v boundary %d", p, v.Populace, q, expected)
+				}
+				if zsl.Percentile(v.Populace, v) != p {
+					t.Fatalf("percentile of %d: %v != %v", v.Uid, zsl.Percentile(v.Populace, v), p)
+				}
+			}
+		}
+		var head = zsl.HeaderNode()
+		if zsl.Percentile(head.Score(), head.Member()) != 0 || zs.Percentile(1) != -1 {
+			t.Fatalf("unexpected percentile of head or missing object")
+		}
+
+		// reverse order of a list is the order of the opposite list
+		var rev = NewZSet()
+		if !descending {
+			rev = NewZSet(WithDescending())
+		}
+		for _, v := range set {
+			rev.Add(v.Populace, v)
+		}
+		var revBoundaries = zsl.RevQuantileBoundaries(qs)
+		for i, q := range qs {
+			var expected, _ = rev.List().ScoreAtQuantile(q)
+			if score, ok := zsl.RevScoreAtQuantile(q); !ok || score != expected || revBoundaries[i] != expected {
+				t.Fatalf("score at reverse quantile %v: %d %d != %d", q, score, revBoundaries[i], expected)
+			}
+		}
+		for _, v := range set {
+			if p := zs.RevPercentile(v.Uid); p != rev.Percentile(v.Uid) || p != zsl.RevPercentile(v.Populace, v) {
+				t.Fatalf("reverse percentile of %d: %v != %v", v.Uid, p, rev.Percentile(v.Uid))
+			}
+		}
+		if zsl.RevPercentile(zsl.TailNode().Score(), zsl.TailNode().Member()) != 0 || zs.RevPercentile(1) != -1 {
+			t.Fatalf("unexpected reverse percentile of tail or missing object")
+		}
+	}
+
+	var zsl = NewOrderedSkipList[string, int]()
+	if _, ok := zsl.ScoreAtQuantile(0.5); ok || zsl.QuantileBoundaries([]float64{0.5}) != nil {
+		t.Fatalf("quantile of empty list")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("invalid quantile should panic")
+			}
+		}()
+		zsl.ScoreAtQuantile(1.5)
+	}()
+}
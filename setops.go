@@ -0,0 +1,260 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// Number is the constraint of scores that can be weighted and summed
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Aggregate is the policy of combining scores of a member in several
+// lists, like AGGREGATE of ZUNIONSTORE.
+type Aggregate uint8
+
+const (
+	AggregateSum Aggregate = iota
+	AggregateMin
+	AggregateMax
+)
+
+type setOpKind uint8
+
+const (
+	setUnion setOpKind = iota
+	setInter
+	setDiff
+)
+
+// setItem is an element of an input list of set operation
+type setItem[K comparable, S Number] struct {
+	obj   K
+	score S   // weighted score
+	list  int // index of the list
+}
+
+// Union return a new list of members in any of `lists`, like ZUNIONSTORE.
+// The score of a member is its scores in lists multiplied by `weights`
+// and combined by `aggregate`, nil `weights` means 1 for all. Like WEIGHTS
+// of redis, weights may be fractional or negative, weighted integer scores
+// are rounded to the nearest integer, and lose precision beyond 2^53
+// unless the weight is 1. A member
+// appearing more than once in a list counts once with its first score in
+// list order. Members are matched by the tie-break
+// policy of lists[0], or by Uuid() for RankInterface members, the object
+// of the first list having it is kept. Integer scores saturate at the
+// limits of S instead of wrapping around, e.g. a negative weight of uint32
+// scores results in 0. The result has the options and
+// tie-break policy of lists[0] and is built in O(N) after sorting.
+// It panics if `lists` is empty, `weights` is not nil and of different
+// length, or lists[0] does not accept a member, e.g. a member without lex
+// key to a lex list.
+func Union[K comparable, S Number](lists []*SkipList[K, S], weights []float64, aggregate Aggregate) *SkipList[K, S] {
+	return combine(lists, weights, aggregate, setUnion)
+}
+
+// Inter return a new list of members in all of `lists`, like ZINTERSTORE,
+// see Union.
+func Inter[K comparable, S Number](lists []*SkipList[K, S], weights []float64, aggregate Aggregate) *SkipList[K, S] {
+	return combine(lists, weights, aggregate, setInter)
+}
+
+// Diff return a new list of members in lists[0] but not in the others with
+// their scores in lists[0], like ZDIFFSTORE, see Union.
+func Diff[K comparable, S Number](lists []*SkipList[K, S]) *SkipList[K, S] {
+	return combine(lists, nil, AggregateSum, setDiff)
+}
+
+// ZUnion return a new set of objects in any of `sets`, objects are matched
+// by uuid, see Union.
+func ZUnion(sets []*ZSet, weights []float64, aggregate Aggregate) *ZSet {
+	return zsetCombine(sets, weights, aggregate, setUnion)
+}
+
+// ZInter return a new set of objects in all of `sets`, objects are matched
+// by uuid, see Union.
+func ZInter(sets []*ZSet, weights []float64, aggregate Aggregate) *ZSet {
+	return zsetCombine(sets, weights, aggregate, setInter)
+}
+
+// ZDiff return a new set of objects in sets[0] but not in the others,
+// objects are matched by uuid, see Union.
+func ZDiff(sets []*ZSet) *ZSet {
+	return zsetCombine(sets, nil, AggregateSum, setDiff)
+}
+
+func zsetCombine(sets []*ZSet, weights []float64, aggregate Aggregate, op setOpKind) *ZSet {
+	var lists = make([]*ZSkipList, len(sets))
+	for i, zs := range sets {
+		lists[i] = zs.zsl
+	}
+	var zs = &ZSet{
+		zsl:  combine(lists, weights, aggregate, op),
+		dict: make(map[uint64]*ZSkipListNode),
+	}
+	zs.rebuildDict()
+	return zs
+}
+
+// memberCompare return the function matching members of set operation
+func memberCompare[K comparable, S cmp.Ordered](zsl *SkipList[K, S]) func(a, b K) int {
+	// a custom tie-break policy may tell objects of the same uuid apart
+	if compare, ok := any(CompareUuid).(func(a, b K) int); ok {
+		return compare
+	}
+	return zsl.compare
+}
+
+// combine compute set operation `op` of `lists`
+func combine[K comparable, S Number](lists []*SkipList[K, S], weights []float64, aggregate Aggregate, op setOpKind) *SkipList[K, S] {
+	if len(lists) == 0 {
+		panic("zskiplist: no list of set operation")
+	}
+	if weights != nil && len(weights) != len(lists) {
+		panic(fmt.Sprintf("zskiplist: %d weights for %d lists", len(weights), len(lists)))
+	}
+	var items []setItem[K, S]
+	for i, zsl := range lists {
+		var weight = 1.0
+		if weights != nil {
+			weight = weights[i]
+		}
+		for x := zsl.head.level[0].forward; x != nil; x = x.level[0].forward {
+			var score = weightScore(x.score, weight)
+			items = append(items, setItem[K, S]{obj: x.obj, score: score, list: i})
+		}
+	}
+
+	// group items of the same member, items of a member stay in list order
+	var compare = memberCompare(lists[0])
+	slices.SortStableFunc(items, func(a, b setItem[K, S]) int {
+		return compare(a.obj, b.obj)
+	})
+	var result = lists[0].emptyClone()
+	var entries = make([]setItem[K, S], 0, len(items))
+	for i := 0; i < len(items); {
+		var e = items[i]
+		var count = 1
+		var first, last = e.list, e.list
+		for i++; i < len(items) && compare(items[i].obj, e.obj) == 0; i++ {
+			if items[i].list == last {
+				continue // counted in this list
+			}
+			count++
+			last = items[i].list
+			e.score = aggregateScore(aggregate, e.score, items[i].score)
+		}
+		switch {
+		case op == setInter && count != len(lists):
+		case op == setDiff && (first != 0 || count != 1):
+		default:
+			entries = append(entries, e)
+		}
+	}
+	slices.SortFunc(entries, func(a, b setItem[K, S]) int {
+		return result.compareElement(a.score, a.obj, b.score, b.obj)
+	})
+	var b = newSortedBuilder(result)
+	for _, e := range entries {
+		// members are unique by the tie-break policy, or by uuid which it
+		// must not tell apart, see NewZSkipListFunc
+		if _, err := b.append(e.score, e.obj); err != nil {
+			panic(fmt.Sprintf("zskiplist: set operation: %v", err))
+		}
+	}
+	result.check()
+	return result
+}
+
+// aggregateScore combine scores `a` and `b` by `agg`
+func aggregateScore[S Number](agg Aggregate, a, b S) S {
+	switch agg {
+	case AggregateMin:
+		return min(a, b)
+	case AggregateMax:
+		return max(a, b)
+	}
+	var sum = addScore(a, b)
+	if sum != sum { // NaN of +inf + -inf
+		return 0
+	}
+	return sum
+}
+
+// scoreLimits return min and max of S if it is an integer type
+func scoreLimits[S Number]() (lo, hi S, integer bool) {
+	var min int64
+	var max uint64
+	switch reflect.TypeFor[S]().Kind() {
+	case reflect.Int8:
+		min, max = math.MinInt8, math.MaxInt8
+	case reflect.Int16:
+		min, max = math.MinInt16, math.MaxInt16
+	case reflect.Int32:
+		min, max = math.MinInt32, math.MaxInt32
+	case reflect.Int:
+		min, max = math.MinInt, math.MaxInt
+	case reflect.Int64:
+		min, max = math.MinInt64, math.MaxInt64
+	case reflect.Uint8:
+		max = math.MaxUint8
+	case reflect.Uint16:
+		max = math.MaxUint16
+	case reflect.Uint32:
+		max = math.MaxUint32
+	case reflect.Uint, reflect.Uintptr:
+		max = math.MaxUint
+	case reflect.Uint64:
+		max = math.MaxUint64
+	default:
+		return lo, hi, false
+	}
+	return S(min), S(max), true
+}
+
+// addScore return a + b, integer overflow saturates at the limits of S
+func addScore[S Number](a, b S) S {
+	var sum = a + b
+	if lo, hi, integer := scoreLimits[S](); integer {
+		if b > 0 && sum < a {
+			return hi
+		}
+		if b < 0 && sum > a {
+			return lo
+		}
+	}
+	return sum
+}
+
+// weightScore return score * weight, integer scores are rounded and
+// saturate at the limits of S.
+func weightScore[S Number](score S, weight float64) S {
+	if weight == 1 {
+		return score
+	}
+	var f = float64(score) * weight
+	if f != f { // NaN of inf * 0
+		return 0
+	}
+	var lo, hi, integer = scoreLimits[S]()
+	switch {
+	case !integer:
+		return S(f)
+	case f >= float64(hi):
+		return hi
+	case f <= float64(lo):
+		return lo
+	}
+	return S(math.Round(f))
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestZSetAlgebra(t *testing.T) {
+	const days = 7
+	const units = 2000
+	var set = makeTestPlayers(units, 1000, true)
+	var sets []*ZSet
+	var weights []float64
+	var scores = make([]map[uint64]uint32, days)
+	for i := 0; i < days; i++ {
+		var zs = NewZSet()
+		scores[i] = make(map[uint64]uint32)
+		for _, v := range set {
+			if rand.Int()%3 != 0 {
+				// different objects of the same uuid are the same member
+				var score = uint32(rand.Int() % 1000)
+				zs.Add(score, &testPlayer{Uid: v.Uid})
+				scores[i][v.Uid] = score
+			}
+		}
+		sets = append(sets, zs)
+		weights = append(weights, float64(i+1))
+	}
+
+	for _, aggregate := range []Aggregate{AggregateSum, AggregateMin, AggregateMax} {
+		var union, inter = make(map[uint64]uint32), make(map[uint64]uint32)
+		for uuid := range set {
+			var count = 0
+			for i := 0; i < days; i++ {
+				var score, found = scores[i][uuid]
+				if !found {
+					continue
+				}
+				score *= uint32(weights[i])
+				if count++; count == 1 {
+					union[uuid] = score
+				} else {
+					union[uuid] = aggregateScore(aggregate, union[uuid], score)
+				}
+			}
+			if count == days {
+				inter[uuid] = union[uuid]
+			}
+		}
+		var zs = ZUnion(sets, weights, aggregate)
+		checkScores(t, zs.List(), union)
+		zs = ZInter(sets, weights, aggregate)
+		checkScores(t, zs.List(), inter)
+	}
+
+	var diff = make(map[uint64]uint32)
+	for uuid, score := range scores[0] {
+		if _, found := scores[1][uuid]; !found {
+			diff[uuid] = score
+		}
+	}
+	var zs = ZDiff(sets[:2])
+	checkScores(t, zs.List(), diff)
+	for uuid := range diff {
+		if !zs.Contains(uuid) {
+			t.Fatalf("%d not in diff set", uuid)
+		}
+	}
+}
+
+func TestSkipListAlgebra(t *testing.T) {
+	var a = NewOrderedSkipList[string, float64](WithDescending())
+	var b = NewOrderedSkipList[string, float64]()
+	a.Insert(1, "x")
+	a.Insert(2, "y")
+	a.Insert(math.Inf(1), "z")
+	b.Insert(3, "y")
+	b.Insert(4, "w")
+	b.Insert(math.Inf(-1), "z")
+
+	var expect = func(zsl *SkipList[string, float64], elements ...any) {
+		t.Helper()
+		if err := zsl.Validate(); err != nil || !zsl.Descending() {
+			t.Fatalf("unexpected result list: %v", err)
+		}
+		if zsl.Len() != len(elements)/2 {
+			t.Fatalf("unexpected element count, %d != %d", zsl.Len(), len(elements)/2)
+		}
+		var x = zsl.HeaderNode()
+		for i := 0; i < len(elements); i += 2 {
+			if x.Member() != elements[i] || x.Score() != elements[i+1] {
+				t.Fatalf("element %d: %s %v != %s %v", i/2, x.Member(), x.Score(), elements[i], elements[i+1])
+			}
+			x = x.Next()
+		}
+	}
+	var lists = []*SkipList[string, float64]{a, b}
+	// +inf + -inf is 0
+	expect(Union(lists, nil, AggregateSum), "y", 5.0, "w", 4.0, "x", 1.0, "z", 0.0)
+	expect(Union(lists, []float64{2, 0.5}, AggregateMax), "z", math.Inf(1), "y", 4.0, "w", 2.0, "x", 2.0)
+	expect(Inter(lists, nil, AggregateMin), "y", 2.0, "z", math.Inf(-1))
+	// +inf * 0 is 0
+	expect(Inter(lists, []float64{0, 1}, AggregateSum), "y", 3.0, "z", math.Inf(-1))
+	expect(Diff(lists), "x", 1.0)
+	expect(Diff(lists[:1]), "z", math.Inf(1), "y", 2.0, "x", 1.0)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("mismatched weights should panic")
+			}
+		}()
+		Union(lists, []float64{1}, AggregateSum)
+	}()
+}
+
+func TestSkipListAlgebraMatch(t *testing.T) {
+	// different objects of the same uuid are the same member
+	var a, b = NewZSkipList(), NewZSkipListFunc(CompareUuidDesc)
+	a.Insert(10, &testPlayer{Uid: 1})
+	b.Insert(20, &testPlayer{Uid: 1})
+	b.Insert(5, &testPlayer{Uid: 2})
+	var zsl = Union([]*ZSkipList{a, b}, nil, AggregateSum)
+	if zsl.Len() != 2 || zsl.GetRank(30, &testPlayer{Uid: 1}) != 2 ||
+		zsl.TailNode().Member() != a.HeaderNode().Member() {
+		t.Fatalf("unexpected union by uuid: %v", zsl)
+	}
+	if zsl = Inter([]*ZSkipList{a, b}, nil, AggregateMax); zsl.Len() != 1 || zsl.HeaderNode().Member() != a.HeaderNode().Member() {
+		t.Fatalf("unexpected intersection by uuid: %v", zsl)
+	}
+
+	// other members are matched by tie-break policy
+	var lastDigit = func(a, b int) int {
+		return cmp.Compare(a%10, b%10)
+	}
+	var c, d = NewSkipList[int, int](lastDigit), NewSkipList[int, int](lastDigit)
+	c.Insert(1, 11)
+	c.Insert(2, 12)
+	d.Insert(3, 21)
+	d.Insert(4, 33)
+	var list = Union([]*SkipList[int, int]{c, d}, nil, AggregateSum)
+	if err := list.Validate(); err != nil || list.Len() != 3 || list.HeaderNode().Member() != 12 || list.TailNode().Score() != 4 {
+		t.Fatalf("unexpected union by tie-break policy: %v %v", list, err)
+	}
+}
+
+func TestSkipListAlgebraSaturate(t *testing.T) {
+	var a, b = NewZSkipList(), NewZSkipList()
+	a.Insert(4e9, &testPlayer{Uid: 1})
+	b.Insert(4e9, &testPlayer{Uid: 1})
+	b.Insert(3e9, &testPlayer{Uid: 2})
+	var zsl = Union([]*ZSkipList{a, b}, []float64{1, 2}, AggregateSum)
+	if zsl.Len() != 2 || zsl.TailNode().Score() != math.MaxUint32 || zsl.HeaderNode().Score() != math.MaxUint32 {
+		t.Fatalf("unexpected saturated union: %v", zsl)
+	}
+	// fractional and negative weights like WEIGHTS of redis
+	zsl = Union([]*ZSkipList{a, b}, []float64{0.5, -1}, AggregateSum)
+	if zsl.GetRank(2e9, &testPlayer{Uid: 1}) == 0 || zsl.GetRank(0, &testPlayer{Uid: 2}) == 0 {
+		t.Fatalf("unexpected weighted union: %v", zsl)
+	}
+
+	for _, c := range []struct {
+		a, b, sum int8
+		weight    float64
+		product   int8
+	}{
+		{100, 100, 127, 2, 127},
+		{-100, -100, -128, -2, 127},
+		{-100, 100, 0, 1.5, -128},
+		{-128, -1, -128, -1, 127},
+		{3, -128, -125, 0.5, 2},
+		{5, -7, -2, -7, -35},
+	} {
+		if sum, product := addScore(c.a, c.b), weightScore(c.a, c.weight); sum != c.sum || product != c.product {
+			t.Fatalf("%d, %d, %v: sum %d product %d", c.a, c.b, c.weight, sum, product)
+		}
+	}
+	if addScore(1.5, math.MaxFloat64) != math.MaxFloat64 || weightScore(uint8(16), 16) != 255 || weightScore(uint8(15), -1) != 0 ||
+		weightScore(math.Inf(1), 0) != 0 || weightScore(uint64(math.MaxUint64), 1) != math.MaxUint64 {
+		t.Fatalf("unexpected float or unsigned scores")
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"reflect"
+)
+
+// Snapshot layout, all integers are big endian:
+//
+//	magic    [4]byte "ZSKL"
+//	version  uint8
+//	flags    uint8, bit 0 is set if list is descending
+//	count    uvarint
+//	elements count * (score, member) in list order
+//	checksum uint32, crc32 IEEE of all above
+//
+// Scores and builtin members are encoded by kind of their type parameter:
+// signed integers as varint, unsigned integers as uvarint, floats as IEEE
+// 754 bits and strings as uvarint length followed by bytes. Members encoded
+// by a MemberCodec are prefixed with uvarint length.
+const (
+	snapshotMagic   = "ZSKL"
+	snapshotVersion = 1
+
+	snapshotFlagDescending = 1 << 0
+)
+
+// MemberCodec encode and decode members for snapshot of a skiplist,
+// it is required if the member type is not an integer or string type, e.g.
+// RankInterface, even if the dynamic type of members is.
+type MemberCodec[K any] interface {
+	// AppendMember append encoded `obj` to `buf` and return the extended buffer
+	AppendMember(buf []byte, obj K) ([]byte, error)
+
+	// DecodeMember decode a member from `data` of AppendMember
+	DecodeMember(data []byte) (K, error)
+}
+
+// UuidCodec encode RankInterface members by their Uuid(),
+// and decode members by creating objects from uuid through the function.
+type UuidCodec func(uuid uint64) (RankInterface, error)
+
+func (f UuidCodec) AppendMember(buf []byte, obj RankInterface) ([]byte, error) {
+	return binary.BigEndian.AppendUint64(buf, obj.Uuid()), nil
+}
+
+func (f UuidCodec) DecodeMember(data []byte) (RankInterface, error) {
+	if len(data) != 8 {
+		return nil, fmt.Errorf("%w: bad uuid size %d", errMalformed, len(data))
+	}
+	return f(binary.BigEndian.Uint64(data))
+}
+
+// SetMemberCodec set codec of members used by snapshot, log and cursors.
+// Snapshot and log restore members from it, so they need a codec unless K
+// is an integer or string type. Cursors only need a member to search, so a
+// ZSkipList ordered by uuid only encodes them by Uuid() without a codec,
+// see WithUuidOrder.
+func (zsl *SkipList[K, S]) SetMemberCodec(codec MemberCodec[K]) {
+	zsl.codec = codec
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (zsl *SkipList[K, S]) MarshalBinary() ([]byte, error) {
+	var buf = make([]byte, 0, 16+zsl.length*8)
+	buf = append(buf, snapshotMagic...)
+	buf = append(buf, snapshotVersion)
+	var flags byte
+	if zsl.descending {
+		flags |= snapshotFlagDescending
+	}
+	buf = append(buf, flags)
+	buf = binary.AppendUvarint(buf, uint64(zsl.length))
+
+	var err error
+	var scratch []byte
+	for x := zsl.head.level[0].forward; x != nil; x = x.level[0].forward {
+		if buf, err = zsl.appendElement(buf, &scratch, x.score, x.obj); err != nil {
+			return nil, err
+		}
+	}
+	return binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf)), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, it replaces all
+// elements of list by the snapshot, which must have the same order and
+// tie-break policy of the list. The list is rebuilt in O(N) and is left
+// unchanged on error. Like BuildFromSorted, the replacement is recorded to
+// the attached log as deletions and insertions, an error wraps ErrLog is
+// returned if it is not written.
+func (zsl *SkipList[K, S]) UnmarshalBinary(data []byte) error {
+	var header = len(snapshotMagic) + 2
+	if len(data) < header+4 || string(data[:len(snapshotMagic)]) != snapshotMagic {
+		return ErrInvalidSnapshot
+	}
+	var body = data[:len(data)-4]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(data[len(body):]) {
+		return ErrChecksum
+	}
+	if version := data[len(snapshotMagic)]; version != snapshotVersion {
+		return fmt.Errorf("%w: unknown version %d", ErrInvalidSnapshot, version)
+	}
+	var descending = data[len(snapshotMagic)+1]&snapshotFlagDescending != 0
+	if descending != zsl.descending {
+		return fmt.Errorf("%w: order mismatch", ErrInvalidSnapshot)
+	}
+	body = body[header:]
+	count, n := binary.Uvarint(body)
+	if n <= 0 {
+		return ErrInvalidSnapshot
+	}
+	body = body[n:]
+
+	// decode into an empty list so the list is kept on error
+	var loaded = zsl.emptyClone()
+	loaded.rnd = zsl.rnd // nodes are moved to list
+	var b = newSortedBuilder(loaded)
+	for i := uint64(0); i < count; i++ {
+		score, obj, n, err := zsl.readElement(body)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+		}
+		body = body[n:]
+		if _, err = b.append(score, obj); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+		}
+	}
+	if len(body) != 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrInvalidSnapshot, len(body))
+	}
+
+	// replaced like BuildFromSorted, deletions and insertions are logged
+	zsl.clear()
+	zsl.head, zsl.tail = loaded.head, loaded.tail
+	zsl.length, zsl.level = loaded.length, loaded.level
+	if zsl.oplog != nil {
+		for x := zsl.head.level[0].forward; x != nil; x = x.level[0].forward {
+			zsl.logInsert(x.score, x.obj)
+		}
+	}
+	zsl.check()
+	return zsl.LogErr()
+}
+
+// checkCodec return an error wraps ErrNoMemberCodec if members cannot be
+// encoded, i.e. no member codec is set and K is not a builtin type.
+func (zsl *SkipList[K, S]) checkCodec() error {
+	if zsl.codec != nil {
+		return nil
+	}
+	var obj K
+	var _, err = appendValue(nil, reflect.ValueOf(&obj).Elem())
+	return err
+}
+
+// appendElement append encoded (score, obj) to `buf`, `scratch` is the
+// reusable buffer for member codec.
+func (zsl *SkipList[K, S]) appendElement(buf []byte, scratch *[]byte, score S, obj K) ([]byte, error) {
+	return encodeElement(zsl.codec, buf, scratch, score, obj)
+}
+
+// readElement decode (score, obj) of appendElement from `data`,
+// return # of bytes read.
+func (zsl *SkipList[K, S]) readElement(data []byte) (S, K, int, error) {
+	return decodeElement[K, S](zsl.codec, data)
+}
+
+// encodeElement append (score, obj) to `buf` with member `codec`, members
+// are encoded as builtin values of type K if `codec` is nil, the same type
+// decodeElement decodes to.
+func encodeElement[K comparable, S cmp.Ordered](codec MemberCodec[K], buf []byte, scratch *[]byte, score S, obj K) ([]byte, error) {
+	var err error
+	if buf, err = appendValue(buf, reflect.ValueOf(&score).Elem()); err != nil {
+		return nil, err
+	}
+	if codec == nil {
+		return appendValue(buf, reflect.ValueOf(&obj).Elem())
+	}
+	if *scratch, err = codec.AppendMember((*scratch)[:0], obj); err != nil {
+		return nil, err
+	}
+	buf = binary.AppendUvarint(buf, uint64(len(*scratch)))
+	return append(buf, *scratch...), nil
+}
+
+// decodeElement decode (score, obj) of encodeElement from `data`,
+// return # of bytes read.
+func decodeElement[K comparable, S cmp.Ordered](codec MemberCodec[K], data []byte) (S, K, int, error) {
+	var score S
+	var obj K
+	n, err := readValue(data, reflect.ValueOf(&score).Elem())
+	if err != nil {
+		return score, obj, 0, err
+	}
+	data = data[n:]
+	if codec == nil {
+		m, err := readValue(data, reflect.ValueOf(&obj).Elem())
+		return score, obj, n + m, err
+	}
+	size, m := binary.Uvarint(data)
+	if m <= 0 || uint64(len(data)-m) < size {
+		return score, obj, 0, errMalformed
+	}
+	obj, err = codec.DecodeMember(data[m : m+int(size)])
+	return score, obj, n + m + int(size), err
+}
+
+// appendValue append an integer, float or string value to `buf`
+func appendValue(buf []byte, v reflect.Value) ([]byte, error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(buf, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.AppendUvarint(buf, v.Uint()), nil
+	case reflect.Float32:
+		return binary.BigEndian.AppendUint32(buf, math.Float32bits(float32(v.Float()))), nil
+	case reflect.Float64:
+		return binary.BigEndian.AppendUint64(buf, math.Float64bits(v.Float())), nil
+	case reflect.String:
+		var s = v.String()
+		buf = binary.AppendUvarint(buf, uint64(len(s)))
+		return append(buf, s...), nil
+	}
+	return nil, fmt.Errorf("%w for type %v", ErrNoMemberCodec, v.Type())
+}
+
+// readValue decode a value of appendValue from `data` to `v`,
+// return # of bytes read.
+func readValue(data []byte, v reflect.Value) (int, error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, n := binary.Varint(data)
+		if n <= 0 || v.OverflowInt(i) {
+			return 0, errMalformed
+		}
+		v.SetInt(i)
+		return n, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, n := binary.Uvarint(data)
+		if n <= 0 || v.OverflowUint(u) {
+			return 0, errMalformed
+		}
+		v.SetUint(u)
+		return n, nil
+	case reflect.Float32:
+		if len(data) < 4 {
+			return 0, errMalformed
+		}
+		v.SetFloat(float64(math.Float32frombits(binary.BigEndian.Uint32(data))))
+		return 4, nil
+	case reflect.Float64:
+		if len(data) < 8 {
+			return 0, errMalformed
+		}
+		v.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(data)))
+		return 8, nil
+	case reflect.String:
+		size, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < size {
+			return 0, errMalformed
+		}
+		v.SetString(string(data[n : n+int(size)]))
+		return n + int(size), nil
+	}
+	return 0, fmt.Errorf("%w for type %v", ErrNoMemberCodec, v.Type())
+}
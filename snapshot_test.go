@@ -0,0 +1,135 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestZSkipListSnapshot(t *testing.T) {
+	const units = 10000
+	var set = makeTestPlayers(units, 1000, true)
+	var zsl = NewZSkipList()
+	for _, v := range set {
+		zsl.Insert(v.Populace, v)
+	}
+	if _, err := zsl.MarshalBinary(); !errors.Is(err, ErrNoMemberCodec) {
+		t.Fatalf("marshal without codec: %v", err)
+	}
+
+	var codec = UuidCodec(func(uuid uint64) (RankInterface, error) {
+		return set[uuid], nil
+	})
+	zsl.SetMemberCodec(codec)
+	data, err := zsl.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	var loaded = NewZSkipList()
+	loaded.SetMemberCodec(codec)
+	loaded.Insert(1, &testPlayer{Uid: 1})
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if loaded.Len() != units {
+		t.Fatalf("unexpected element count, %d != %d", loaded.Len(), units)
+	}
+	if err := loaded.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	for _, v := range set {
+		if a, b := zsl.GetRank(v.Populace, v), loaded.GetRank(v.Populace, v); a != b {
+			t.Fatalf("rank of %v: %d != %d", v, b, a)
+		}
+	}
+	for rank := 1; rank <= units; rank++ {
+		if zsl.GetElementByRank(rank).Member() != loaded.GetElementByRank(rank).Member() {
+			t.Fatalf("element at rank %d mismatch", rank)
+		}
+	}
+	// list is still usable after load
+	manyUpdate(t, loaded, set, units/2)
+	if loaded.Len() != units {
+		t.Fatalf("unexpected element count after update, %d != %d", loaded.Len(), units)
+	}
+
+	// a failed load keeps the list
+	var deleted = NewZSkipList()
+	deleted.SetMemberCodec(UuidCodec(func(uuid uint64) (RankInterface, error) {
+		if uuid%100 == 0 {
+			return nil, ErrNotFound
+		}
+		return set[uuid], nil
+	}))
+	deleted.Insert(1, &testPlayer{Uid: 1})
+	if err := deleted.UnmarshalBinary(data); !errors.Is(err, ErrNotFound) || deleted.Len() != 1 {
+		t.Fatalf("unmarshal with failed codec: %v, %d elements", err, deleted.Len())
+	}
+
+	// a load is recorded to log like BuildFromSorted
+	var replayed = NewZSkipList()
+	replayed.SetMemberCodec(codec)
+	if before, err := loaded.MarshalBinary(); err != nil || replayed.UnmarshalBinary(before) != nil {
+		t.Fatalf("copy list: %v", err)
+	}
+	var wal bytes.Buffer
+	if err := loaded.AttachLog(&wal); err != nil {
+		t.Fatalf("AttachLog: %v", err)
+	}
+	if err := loaded.UnmarshalBinary(data); err != nil || loaded.Len() != units {
+		t.Fatalf("UnmarshalBinary with log: %v", err)
+	}
+	if err := replayed.Replay(bytes.NewReader(wal.Bytes())); err != nil || replayed.Len() != units {
+		t.Fatalf("Replay: %v, %d != %d", err, replayed.Len(), units)
+	}
+	for rank := 1; rank <= units; rank++ {
+		if replayed.GetElementByRank(rank).Member() != loaded.GetElementByRank(rank).Member() {
+			t.Fatalf("replayed element at rank %d mismatch", rank)
+		}
+	}
+
+	data[len(data)/2] ^= 0xFF
+	if err := loaded.UnmarshalBinary(data); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("unmarshal corrupted data: %v", err)
+	}
+	if err := NewZSkipList(WithDescending()).UnmarshalBinary(data[:8]); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Fatalf("unmarshal truncated data: %v", err)
+	}
+}
+
+func TestSkipListSnapshotBuiltin(t *testing.T) {
+	var zsl = NewOrderedSkipList[string, float64](WithDescending())
+	zsl.Insert(-1.5, "alice")
+	zsl.Insert(3.25, "bob")
+	zsl.Insert(3.25, "carol")
+	data, err := zsl.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	if err := NewOrderedSkipList[string, float64]().UnmarshalBinary(data); !errors.Is(err, ErrInvalidSnapshot) {
+		t.Fatalf("unmarshal to ascend list: %v", err)
+	}
+	var loaded = NewOrderedSkipList[string, float64](WithDescending())
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if loaded.Len() != zsl.Len() {
+		t.Fatalf("unexpected element count, %d != %d", loaded.Len(), zsl.Len())
+	}
+	for x, y := zsl.HeaderNode(), loaded.HeaderNode(); x != nil; x, y = x.Next(), y.Next() {
+		if x.Member() != y.Member() || x.Score() != y.Score() {
+			t.Fatalf("loaded element mismatch, %v-%v != %v-%v", y.Member(), y.Score(), x.Member(), x.Score())
+		}
+	}
+	// members are encoded by the member type rather than dynamic type
+	var keys = NewZSkipList()
+	keys.Insert(1, UuidKey(1))
+	if _, err := keys.MarshalBinary(); !errors.Is(err, ErrNoMemberCodec) {
+		t.Fatalf("marshal integer members of interface type: %v", err)
+	}
+}
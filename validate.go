@@ -0,0 +1,112 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// Validate verify structure of list: ordering of elements, spans of every
+// level, backward links, tail, length and level. Returns an error wraps
+// ErrCorrupted which describes the first offending node, e.g. when the
+// tie-break key of a member is changed in place. It is O(N) and mostly for
+// tests.
+func (zsl *SkipList[K, S]) Validate() error {
+	if zsl.level < 1 || zsl.level > len(zsl.head.level) {
+		return fmt.Errorf("%w: level %d out of range", ErrCorrupted, zsl.level)
+	}
+	for i := zsl.level; i < len(zsl.head.level); i++ {
+		if zsl.head.level[i].forward != nil {
+			return fmt.Errorf("%w: head has forward at level %d above list level %d", ErrCorrupted, i+1, zsl.level)
+		}
+	}
+	if zsl.level > 1 && zsl.head.level[zsl.level-1].forward == nil {
+		return fmt.Errorf("%w: level %d is empty", ErrCorrupted, zsl.level)
+	}
+
+	// level 0: order, backward links, tail and length
+	var ranks = make(map[*SkipListNode[K, S]]int, zsl.length)
+	var counts = make([]int, zsl.level)
+	var rank = 0
+	var prev *SkipListNode[K, S]
+	for x := zsl.head.level[0].forward; x != nil; x = x.level[0].forward {
+		rank++
+		if _, found := ranks[x]; found {
+			return fmt.Errorf("%w: cycle at rank %d", ErrCorrupted, rank)
+		}
+		ranks[x] = rank
+		if len(x.level) < 1 || len(x.level) > zsl.level {
+			return fmt.Errorf("%w: node %s at rank %d has level %d", ErrCorrupted, nodeString(x), rank, len(x.level))
+		}
+		for i := range x.level {
+			counts[i]++
+		}
+		if x.backward != prev {
+			return fmt.Errorf("%w: node %s at rank %d has wrong backward link", ErrCorrupted, nodeString(x), rank)
+		}
+		if prev != nil && zsl.compareTo(prev, x.score, x.obj) >= 0 {
+			return fmt.Errorf("%w: node %s at rank %d is out of order", ErrCorrupted, nodeString(x), rank)
+		}
+		prev = x
+	}
+	if zsl.tail != prev {
+		return fmt.Errorf("%w: tail is not the last node", ErrCorrupted)
+	}
+	if zsl.length != rank {
+		return fmt.Errorf("%w: length %d but %d nodes", ErrCorrupted, zsl.length, rank)
+	}
+
+	// every level: spans and nodes linked
+	for i := 0; i < zsl.level; i++ {
+		var count = 0
+		var x, xrank = zsl.head, 0
+		for {
+			var next = x.level[i].forward
+			var nextRank = zsl.length
+			if next != nil {
+				var found bool
+				if nextRank, found = ranks[next]; !found {
+					return fmt.Errorf("%w: level %d links to node out of list", ErrCorrupted, i+1)
+				}
+				if len(next.level) <= i {
+					return fmt.Errorf("%w: node %s at rank %d linked at level %d", ErrCorrupted, nodeString(next), nextRank, i+1)
+				}
+				if next.level[i].backward != x {
+					return fmt.Errorf("%w: node %s at rank %d has wrong backward link at level %d", ErrCorrupted, nodeString(next), nextRank, i+1)
+				}
+			}
+			if x.level[i].span != nextRank-xrank {
+				return fmt.Errorf("%w: node %s at rank %d has span %d at level %d, expect %d",
+					ErrCorrupted, nodeString(x), xrank, x.level[i].span, i+1, nextRank-xrank)
+			}
+			if next == nil {
+				break
+			}
+			if nextRank <= xrank {
+				return fmt.Errorf("%w: level %d links backward at rank %d", ErrCorrupted, i+1, nextRank)
+			}
+			count++
+			x, xrank = next, nextRank
+		}
+		if count != counts[i] {
+			return fmt.Errorf("%w: %d nodes linked at level %d, expect %d", ErrCorrupted, count, i+1, counts[i])
+		}
+	}
+	return nil
+}
+
+// check validate list after modification in debug mode, panic if failed
+func (zsl *SkipList[K, S]) check() {
+	if zsl.debug {
+		if err := zsl.Validate(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func nodeString[K comparable, S cmp.Ordered](x *SkipListNode[K, S]) string {
+	return fmt.Sprintf("<%s %v>", memberString(x.obj), x.score)
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestZSkipListValidate(t *testing.T) {
+	const units = 2000
+	var set = makeTestPlayers(units, 500, true)
+	var zsl = NewZSkipList(WithDebug())
+	for _, v := range set {
+		zsl.Insert(v.Populace, v)
+	}
+	manyUpdate(t, zsl, set, units/2)
+	for _, v := range set {
+		var oldScore = v.Populace
+		v.Populace = v.Populace/2 + 1
+		zsl.UpdateScore(oldScore, v, v.Populace)
+	}
+	zsl.DeleteRangeByScore(RangeSpec[uint32]{Min: 100, Max: 150}, nil)
+	zsl.DeleteRangeByRank(10, 100, nil)
+	if err := zsl.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	// break order by modifying score of node
+	var node = zsl.GetElementByRank(zsl.Len() / 2)
+	var score = node.Score()
+	node.score = zsl.TailNode().Score() + 1
+	if err := zsl.Validate(); !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("Validate broken order: %v", err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("debug mode should panic on corrupted list")
+			}
+		}()
+		zsl.Insert(1, &testPlayer{Uid: 1})
+	}()
+	node.score = score
+
+	// break span
+	node.level[0].span++
+	if err := zsl.Validate(); !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("Validate broken span: %v", err)
+	}
+	node.level[0].span--
+
+	// break backward link
+	var next = node.Next()
+	next.backward = nil
+	if err := zsl.Validate(); !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("Validate broken backward: %v", err)
+	}
+	next.backward = node
+	if err := NewZSkipList().Validate(); err != nil {
+		t.Fatalf("Validate empty list: %v", err)
+	}
+}
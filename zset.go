@@ -0,0 +1,309 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"fmt"
+	"io"
+	"iter"
+)
+
+// ZSet is a sorted set of RankInterface objects, it pairs a ZSkipList with
+// a uuid->node dict like the zset of redis, so members can be looked up
+// without knowing their score.
+// ZSet is not safe for concurrent use, see ConcurrentZSkipList.
+type ZSet struct {
+	zsl  *ZSkipList
+	dict map[uint64]*ZSkipListNode
+}
+
+func NewZSet(opts ...Option) *ZSet {
+	var zs = NewZSetFunc(CompareUuid, opts...)
+	zs.zsl.uuidOrder = true
+	return zs
+}
+
+// NewZSetFunc create a ZSet with a custom tie-break policy, see NewZSkipListFunc.
+// The tie-break key of an object must not be changed while it is in set,
+// pass a new object to Add instead.
+func NewZSetFunc(compare func(a, b RankInterface) int, opts ...Option) *ZSet {
+	return &ZSet{
+		zsl:  NewZSkipListFunc(compare, opts...),
+		dict: make(map[uint64]*ZSkipListNode),
+	}
+}
+
+// NewZSetLex create a ZSet of LexInterface objects ordered by CompareLex on
+// score ties, see NewZSkipListLex.
+func NewZSetLex(opts ...Option) *ZSet {
+	return &ZSet{
+		zsl:  NewZSkipListLex(opts...),
+		dict: make(map[uint64]*ZSkipListNode),
+	}
+}
+
+// Len return # of items in set
+func (zs *ZSet) Len() int {
+	return zs.zsl.Len()
+}
+
+// List return the underlying skiplist, it should only be used for read.
+func (zs *ZSet) List() *ZSkipList {
+	return zs.zsl
+}
+
+// Add add obj with score to set, or update its score if already exist.
+// Returns nil if the set does not accept obj, e.g. an object without lex
+// key to a lex set.
+func (zs *ZSet) Add(score uint32, obj RankInterface) *ZSkipListNode {
+	var uuid = obj.Uuid()
+	if node, found := zs.dict[uuid]; found {
+		node, _ = zs.zsl.updateNode(node, score, obj)
+		return node
+	}
+	var node = zs.zsl.Insert(score, obj)
+	if node != nil {
+		zs.dict[uuid] = node
+	}
+	return node
+}
+
+// Contains test if an object with `uuid` is in set
+func (zs *ZSet) Contains(uuid uint64) bool {
+	_, found := zs.dict[uuid]
+	return found
+}
+
+// Get return the object with `uuid`, or nil if not found
+func (zs *ZSet) Get(uuid uint64) RankInterface {
+	if node, found := zs.dict[uuid]; found {
+		return node.obj
+	}
+	return nil
+}
+
+// Score return score of the object with `uuid`
+func (zs *ZSet) Score(uuid uint64) (uint32, bool) {
+	if node, found := zs.dict[uuid]; found {
+		return node.score, true
+	}
+	return 0, false
+}
+
+// Rank return 1-based rank in list order of the object with `uuid`, 0 if not found
+func (zs *ZSet) Rank(uuid uint64) int {
+	if node, found := zs.dict[uuid]; found {
+		return zs.zsl.RankOf(node)
+	}
+	return 0
+}
+
+// CompetitionRank return rank of the object with `uuid` shared with its
+// ties like "1, 2, 2, 4", 0 if not found, see ZSkipList.CompetitionRank.
+func (zs *ZSet) CompetitionRank(uuid uint64) int {
+	if node, found := zs.dict[uuid]; found {
+		return zs.zsl.CompetitionRank(node.score)
+	}
+	return 0
+}
+
+// DenseRank return rank of the object with `uuid` shared with its ties
+// like "1, 2, 2, 3", 0 if not found, see ZSkipList.DenseRank.
+func (zs *ZSet) DenseRank(uuid uint64) int {
+	if node, found := zs.dict[uuid]; found {
+		return zs.zsl.DenseRank(node.score)
+	}
+	return 0
+}
+
+// Percentile return fraction of objects before the score of the object with
+// `uuid` in list order, -1 if not found, see ZSkipList.Percentile.
+func (zs *ZSet) Percentile(uuid uint64) float64 {
+	if node, found := zs.dict[uuid]; found {
+		return zs.zsl.Percentile(node.score, node.obj)
+	}
+	return -1
+}
+
+// RevPercentile return fraction of objects after the score of the object
+// with `uuid` in list order, -1 if not found, see ZSkipList.RevPercentile.
+func (zs *ZSet) RevPercentile(uuid uint64) float64 {
+	if node, found := zs.dict[uuid]; found {
+		return zs.zsl.RevPercentile(node.score, node.obj)
+	}
+	return -1
+}
+
+// Remove remove the object with `uuid`, return the removed node or nil
+func (zs *ZSet) Remove(uuid uint64) *ZSkipListNode {
+	var node, found = zs.dict[uuid]
+	if !found {
+		return nil
+	}
+	delete(zs.dict, uuid)
+	return zs.zsl.Delete(node.score, node.obj)
+}
+
+// RemoveRangeByScore remove all objects with score in range,
+// return # of removed objects.
+func (zs *ZSet) RemoveRangeByScore(r RangeSpec[uint32]) int {
+	return zs.zsl.DeleteRangeByScore(r, zs.unlink)
+}
+
+// RemoveRangeByRank remove all objects with rank in [start, end],
+// return # of removed objects.
+func (zs *ZSet) RemoveRangeByRank(start, end int) int {
+	return zs.zsl.DeleteRangeByRank(start, end, zs.unlink)
+}
+
+// PopMin remove and return at most `n` objects of the lowest scores,
+// see ZSkipList.PopMin.
+func (zs *ZSet) PopMin(n int) []*ZSkipListNode {
+	var nodes = zs.zsl.PopMin(n)
+	for _, node := range nodes {
+		zs.unlink(node)
+	}
+	return nodes
+}
+
+// PopMax remove and return at most `n` objects of the highest scores,
+// see ZSkipList.PopMax.
+func (zs *ZSet) PopMax(n int) []*ZSkipListNode {
+	var nodes = zs.zsl.PopMax(n)
+	for _, node := range nodes {
+		zs.unlink(node)
+	}
+	return nodes
+}
+
+// RemoveRangeByLex remove all objects with key in lex range of a lex set,
+// return # of removed objects.
+func (zs *ZSet) RemoveRangeByLex(r LexRangeSpec) int {
+	return zs.zsl.DeleteRangeByLex(r, zs.unlink)
+}
+
+// ApplyBatch apply `changes` to set and return the outcome of each change,
+// see ZSkipList.ApplyBatch. Old score and object of delete and update are
+// taken from the set, so Old may be left zero, and an update replaces the
+// object like Add, which may change its tie-break key. An object changed
+// more than once in a batch fails with ErrDuplicate except the first change.
+func (zs *ZSet) ApplyBatch(changes []Change[RankInterface, uint32]) []error {
+	var errs = make([]error, len(changes))
+	var batch = make([]Change[RankInterface, uint32], len(changes))
+	var olds = make([]RankInterface, len(changes))
+	var seen = make(map[uint64]bool, len(changes))
+	for i, c := range changes {
+		var uuid = c.Obj.Uuid()
+		if seen[uuid] {
+			errs[i] = ErrDuplicate
+			continue
+		}
+		seen[uuid] = true
+		var node, found = zs.dict[uuid]
+		switch {
+		case !c.Op.valid():
+			errs[i] = ErrUnknownOp
+		case c.Op == ChangeInsert && found:
+			errs[i] = ErrDuplicate
+		case c.Op != ChangeInsert && !found:
+			errs[i] = ErrNotFound
+		case found:
+			c.Old, olds[i] = node.score, node.obj
+		}
+		batch[i] = c
+	}
+	zs.zsl.applyBatch(batch, olds, errs, func(i int, x *ZSkipListNode) {
+		switch batch[i].Op {
+		case ChangeInsert:
+			zs.dict[x.obj.Uuid()] = x
+		case ChangeDelete:
+			delete(zs.dict, x.obj.Uuid())
+		}
+	})
+	return errs
+}
+
+func (zs *ZSet) unlink(node *ZSkipListNode) {
+	delete(zs.dict, node.obj.Uuid())
+}
+
+// SetMemberCodec set codec of objects used by snapshot, see UuidCodec
+func (zs *ZSet) SetMemberCodec(codec MemberCodec[RankInterface]) {
+	zs.zsl.SetMemberCodec(codec)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (zs *ZSet) MarshalBinary() ([]byte, error) {
+	return zs.zsl.MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, all objects of set
+// are replaced by the snapshot, the set is left unchanged on error.
+func (zs *ZSet) UnmarshalBinary(data []byte) error {
+	if err := zs.zsl.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	zs.rebuildDict()
+	return nil
+}
+
+// AppendSorted append objects of `seq` in O(1) expected each, they must be
+// in list order and after the last object, see ZSkipList.AppendSorted.
+// Returns an error wraps ErrDuplicate if an object is already in set.
+func (zs *ZSet) AppendSorted(seq iter.Seq2[RankInterface, uint32]) error {
+	var zsl = zs.zsl
+	defer zsl.check()
+	var b = newSortedBuilder(zsl)
+	for obj, score := range seq {
+		var uuid = obj.Uuid()
+		if _, found := zs.dict[uuid]; found {
+			return fmt.Errorf("%w: %s", ErrDuplicate, memberString(obj))
+		}
+		var node, err = b.append(score, obj)
+		if err != nil {
+			return err
+		}
+		zsl.logInsert(score, obj)
+		zs.dict[uuid] = node
+	}
+	return zsl.LogErr()
+}
+
+// BuildFromSorted replace all objects of set by objects of `seq` in O(N),
+// see AppendSorted.
+func (zs *ZSet) BuildFromSorted(seq iter.Seq2[RankInterface, uint32]) error {
+	zs.zsl.clear()
+	clear(zs.dict)
+	return zs.AppendSorted(seq)
+}
+
+// AttachLog record following changes of set to `w`, see ZSkipList.AttachLog
+func (zs *ZSet) AttachLog(w io.Writer) error {
+	return zs.zsl.AttachLog(w)
+}
+
+// LogErr return the first error of writing log
+func (zs *ZSet) LogErr() error {
+	return zs.zsl.LogErr()
+}
+
+// RewriteLog write current objects of set to `w` as a new log and attach it
+func (zs *ZSet) RewriteLog(w io.Writer) error {
+	return zs.zsl.RewriteLog(w)
+}
+
+// Replay apply all records of log in `r` to set, see ZSkipList.Replay
+func (zs *ZSet) Replay(r io.Reader) error {
+	var err = zs.zsl.Replay(r)
+	zs.rebuildDict()
+	return err
+}
+
+func (zs *ZSet) rebuildDict() {
+	clear(zs.dict)
+	for x := zs.zsl.HeaderNode(); x != nil; x = x.Next() {
+		zs.dict[x.obj.Uuid()] = x
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright (C) 2017 ichenq@outlook.com. All rights reserved.
+// Distributed under the terms and conditions of the MIT License.
+// See accompanying files LICENSE.
+
+package zskiplist
+
+import (
+	"cmp"
+	"testing"
+)
+
+func TestZSetAddRemove(t *testing.T) {
+	const units = 10000
+	var set = makeTestPlayers(units, 1000, true)
+	var zs = NewZSet()
+	for _, v := range set {
+		if node := zs.Add(v.Populace, v); node == nil {
+			t.Fatalf("add item[%d-%d] failed", v.Populace, v.Uid)
+		}
+	}
+	if zs.Len() != units {
+		t.Fatalf("unexpected set element count, %d != %d", zs.Len(), units)
+	}
+	for _, v := range set {
+		if score, ok := zs.Score(v.Uid); !ok || score != v.Populace {
+			t.Fatalf("score of %d: %d != %d", v.Uid, score, v.Populace)
+		}
+		if rank := zs.Rank(v.Uid); rank != zs.List().GetRank(v.Populace, v) {
+			t.Fatalf("rank of %d mismatch: %d", v.Uid, rank)
+		}
+	}
+
+	// update score of all items
+	for _, v := range set {
+		v.Populace += 10
+		zs.Add(v.Populace, v)
+	}
+	if zs.Len() != units {
+		t.Fatalf("unexpected set element count after update, %d != %d", zs.Len(), units)
+	}
+	for _, v := range set {
+		if score, _ := zs.Score(v.Uid); score != v.Populace {
+			t.Fatalf("updated score of %d: %d != %d", v.Uid, score, v.Populace)
+		}
+	}
+
+	for _, v := range set {
+		if node := zs.Remove(v.Uid); node == nil || node.Member().Uuid() != v.Uid {
+			t.Fatalf("remove item %d failed", v.Uid)
+		}
+		if zs.Contains(v.Uid) {
+			t.Fatalf("item %d still in set", v.Uid)
+		}
+	}
+	if zs.Len() != 0 {
+		t.Fatalf("set expected empty, but got size: %d", zs.Len())
+	}
+	if zs.Remove(1) != nil || zs.Rank(1) != 0 || zs.Get(1) != nil {
+		t.Fatalf("unexpected result on missing item")
+	}
+}
+
+func TestZSetRemoveRange(t *testing.T) {
+	var zs = NewZSet()
+	for i := 1; i <= 100; i++ {
+		zs.Add(uint32(i), &testPlayer{Uid: uint64(i), Populace: uint32(i)})
+	}
+	if n := zs.RemoveRangeByScore(RangeSpec[uint32]{Min: 10, Max: 20, MaxEx: true}); n != 10 {
+		t.Fatalf("remove by score: %d != 10", n)
+	}
+	if zs.Contains(10) || zs.Contains(19) || !zs.Contains(20) {
+		t.Fatalf("unexpected index after remove by score")
+	}
+	if n := zs.RemoveRangeByRank(1, 9); n != 9 {
+		t.Fatalf("remove by rank: %d != 9", n)
+	}
+	if zs.Contains(9) || zs.Rank(20) != 1 || zs.Len() != 81 {
+		t.Fatalf("unexpected index after remove by rank")
+	}
+}
+
+type timedPlayer struct {
+	uid uint64
+	at  int64 // time of reaching current score
+}
+
+func (p *timedPlayer) Uuid() uint64 {
+	return p.uid
+}
+
+func TestZSetTieBreak(t *testing.T) {
+	// the one reaches a score earlier ranks higher
+	var zs = NewZSetFunc(func(a, b RankInterface) int {
+		if c := cmp.Compare(b.(*timedPlayer).at, a.(*timedPlayer).at); c != 0 {
+			return c
+		}
+		return CompareUuid(a, b)
+	})
+	zs.Add(100, &timedPlayer{uid: 1, at: 30})
+	zs.Add(100, &timedPlayer{uid: 2, at: 10})
+	zs.Add(100, &timedPlayer{uid: 3, at: 20})
+	zs.Add(90, &timedPlayer{uid: 4, at: 5})
+	var expected = []uint64{2, 3, 1, 4}
+	for i, uuid := range expected {
+		if rank := zs.Len() - zs.Rank(uuid) + 1; rank != i+1 {
+			t.Fatalf("rank of %d: %d != %d", uuid, rank, i+1)
+		}
+	}
+
+	// uid 4 reaches 100 latest
+	zs.Add(100, &timedPlayer{uid: 4, at: 40})
+	expected = []uint64{2, 3, 1, 4}
+	for i, uuid := range expected {
+		if rank := zs.Len() - zs.Rank(uuid) + 1; rank != i+1 {
+			t.Fatalf("rank of %d after update: %d != %d", uuid, rank, i+1)
+		}
+	}
+	// uid 1 reaches 100 again earliest
+	zs.Add(100, &timedPlayer{uid: 1, at: 1})
+	expected = []uint64{1, 2, 3, 4}
+	for i, uuid := range expected {
+		if rank := zs.Len() - zs.Rank(uuid) + 1; rank != i+1 {
+			t.Fatalf("rank of %d after tie-break update: %d != %d", uuid, rank, i+1)
+		}
+	}
+	// uid 3 reaches 100 again earliest in a batch, and uid 1 is removed
+	var errs = zs.ApplyBatch([]Change[RankInterface, uint32]{
+		{Op: ChangeUpdate, Obj: &timedPlayer{uid: 3, at: 0}, New: 100},
+		{Op: ChangeDelete, Obj: &timedPlayer{uid: 1}},
+	})
+	if errs[0] != nil || errs[1] != nil || zs.Get(3).(*timedPlayer).at != 0 || zs.Contains(1) {
+		t.Fatalf("batch tie-break update: %v", errs)
+	}
+	expected = []uint64{3, 2, 4}
+	for i, uuid := range expected {
+		if rank := zs.Len() - zs.Rank(uuid) + 1; rank != i+1 {
+			t.Fatalf("rank of %d after batch update: %d != %d", uuid, rank, i+1)
+		}
+	}
+	if err := zs.List().Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	var zsl = NewZSkipListFunc(CompareUuidDesc)
+	for i := 1; i <= 5; i++ {
+		zsl.Insert(10, &testPlayer{Uid: uint64(i)})
+	}
+	if node := zsl.GetElementByRank(1); node.Member().Uuid() != 5 {
+		t.Fatalf("first element of uuid descend list: %d", node.Member().Uuid())
+	}
+}
+
+func TestZSetSnapshot(t *testing.T) {
+	var players = make(map[uint64]*testPlayer)
+	var codec = UuidCodec(func(uuid uint64) (RankInterface, error) {
+		return players[uuid], nil
+	})
+	var zs = NewZSet()
+	zs.SetMemberCodec(codec)
+	for i := 1; i <= 100; i++ {
+		var p = &testPlayer{Uid: uint64(i), Populace: uint32(i % 7)}
+		players[p.Uid] = p
+		zs.Add(p.Populace, p)
+	}
+	data, err := zs.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	var loaded = NewZSet()
+	loaded.SetMemberCodec(codec)
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	for uuid, p := range players {
+		if score, _ := loaded.Score(uuid); score != p.Populace || loaded.Rank(uuid) != zs.Rank(uuid) {
+			t.Fatalf("loaded item %d mismatch", uuid)
+		}
+	}
+}
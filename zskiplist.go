@@ -13,136 +13,358 @@ package zskiplist
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"math/rand"
 )
 
 const (
-	ZSKIPLIST_MAXLEVEL = 12   // Should be enough
-	ZSKIPLIST_P        = 0.25 // Skiplist P = 1/4
+	ZSKIPLIST_MAXLEVEL       = 12   // Should be enough for 16M elements
+	ZSKIPLIST_MAXLEVEL_LIMIT = 32   // Upper bound of configurable max level
+	ZSKIPLIST_P              = 0.25 // Skiplist P = 1/4
 )
 
-//A type that satisfies RankInterface can be ranked in a zskiplist
+// A type that satisfies RankInterface can be ranked in a zskiplist
 type RankInterface interface {
 
 	// Unique id of this object
 	Uuid() uint64
 }
 
-// each level of list node
-type zskipListLevel struct {
-	forward *ZSkipListNode // link to next node
-	span    int            // node # between this and forward link
+// each level of list node. Unlike redis, every level has a backward link,
+// so a finger search climbs and moves backward at upper levels in O(log d)
+// for distance d, and Rank of a node and PopMax walk back without search,
+// level 0 backward alone would cost O(d) for a backward move. It makes a
+// level 24 bytes rather than 16 on 64-bit platforms, i.e. 8/(1-P) bytes
+// more per element, about 11 bytes with the default P, and every link or
+// unlink updates it as well.
+type zskipListLevel[K comparable, S cmp.Ordered] struct {
+	forward  *SkipListNode[K, S] // link to next node
+	backward *SkipListNode[K, S] // link to previous node, head for the first one
+	span     int                 // node # between this and forward link
 }
 
-// list node
-type ZSkipListNode struct {
-	Obj      RankInterface
-	Score    uint32
-	backward *ZSkipListNode
-	level    []zskipListLevel
+// SkipListNode is a list node holding a member and its score
+type SkipListNode[K comparable, S cmp.Ordered] struct {
+	obj      K
+	score    S
+	backward *SkipListNode[K, S]
+	level    []zskipListLevel[K, S]
 }
 
-func newZSkipListNode(level int, score uint32, obj RankInterface) *ZSkipListNode {
-	return &ZSkipListNode{
-		Obj:   obj,
-		Score: score,
-		level: make([]zskipListLevel, level),
+// ZSkipListNode is the list node of ZSkipList
+type ZSkipListNode = SkipListNode[RankInterface, uint32]
+
+func newSkipListNode[K comparable, S cmp.Ordered](level int, score S, obj K) *SkipListNode[K, S] {
+	return &SkipListNode[K, S]{
+		obj:   obj,
+		score: score,
+		level: make([]zskipListLevel[K, S], level),
 	}
 }
 
-func (n *ZSkipListNode) Before() *ZSkipListNode {
+// Member return member of node
+func (n *SkipListNode[K, S]) Member() K {
+	return n.obj
+}
+
+// Score return score of node, use UpdateScore or UpdateNodeScore to change it
+func (n *SkipListNode[K, S]) Score() S {
+	return n.score
+}
+
+// Rank return the 1-based rank of node in its list by walking backward to
+// head along the highest levels, which is O(log N) expected. The result is
+// undefined if the node is not in a list.
+func (n *SkipListNode[K, S]) Rank() int {
+	var rank = 0
+	for x := n; ; {
+		var i = len(x.level) - 1
+		var prev = x.level[i].backward
+		if prev == nil { // head
+			return rank
+		}
+		rank += prev.level[i].span
+		x = prev
+	}
+}
+
+func (n *SkipListNode[K, S]) Before() *SkipListNode[K, S] {
 	return n.backward
 }
 
 // Next return next forward pointer
-func (n *ZSkipListNode) Next() *ZSkipListNode {
+func (n *SkipListNode[K, S]) Next() *SkipListNode[K, S] {
 	return n.level[0].forward
 }
 
-// ZSkipList with ascend order
-type ZSkipList struct {
-	head   *ZSkipListNode // header node
-	tail   *ZSkipListNode // tail node, this means the largest item
-	length int            // count of items
-	level  int            //
+// SkipList with ascend order of score by default, members of equal score
+// are ordered by `compare`, which is the tie-break policy of the list.
+// Scores must not be NaN, same as redis.
+type SkipList[K comparable, S cmp.Ordered] struct {
+	head       *SkipListNode[K, S] // header node
+	tail       *SkipListNode[K, S] // tail node, this means the last item in list order
+	length     int                 // count of items
+	level      int                 //
+	compare    func(a, b K) int    // order of members with same score
+	lexKey     func(K) []byte      // lex key of members, nil if not a lex list
+	descending bool                // list is in descend order of score
+	codec      MemberCodec[K]      // codec of members for snapshot and log
+	oplog      *opLog              // write-ahead log of operations
+	logger     *slog.Logger        // logger of warnings, nil to disable
+	debug      bool                // validate list after modification
+	p          float64             // promotion probability of level
+	rnd        *rand.Rand          // random source of level, nil to use global
+	uuidOrder  bool                // members of same score are ordered by uuid only
+	accept     func(K) error       // check of members to insert, nil to accept all
 }
 
-func NewZSkipList() *ZSkipList {
-	return &ZSkipList{
-		level: 1,
-		head:  newZSkipListNode(ZSKIPLIST_MAXLEVEL, 0, nil),
+// ZSkipList ranks RankInterface objects by uint32 score, ties are broken
+// by Uuid() ascending.
+type ZSkipList = SkipList[RankInterface, uint32]
+
+// NewSkipList create a list whose members of same score are ordered by
+// `compare`, which returns -1, 0, +1 like cmp.Compare. Two members are
+// the same element when `compare` returns 0.
+func NewSkipList[K comparable, S cmp.Ordered](compare func(a, b K) int, opts ...Option) *SkipList[K, S] {
+	var o = newOptions(opts)
+	var zero S
+	var obj K
+	return &SkipList[K, S]{
+		level:      1,
+		head:       newSkipListNode(o.maxLevel, zero, obj),
+		compare:    compare,
+		descending: o.descending,
+		logger:     o.logger,
+		debug:      o.debug,
+		p:          o.p,
+		rnd:        o.rnd,
+		uuidOrder:  o.uuidOrder,
 	}
 }
 
+// NewOrderedSkipList create a list whose members are ordered naturally,
+// e.g. string or integer member ids.
+func NewOrderedSkipList[K cmp.Ordered, S cmp.Ordered](opts ...Option) *SkipList[K, S] {
+	return NewSkipList[K, S](cmp.Compare[K], opts...)
+}
+
+// NewZSkipList create a ZSkipList with tie-break by Uuid() ascending
+func NewZSkipList(opts ...Option) *ZSkipList {
+	var zsl = NewSkipList[RankInterface, uint32](CompareUuid, opts...)
+	zsl.uuidOrder = true
+	return zsl
+}
+
+// NewZSkipListFunc create a ZSkipList with a custom tie-break policy,
+// e.g. the one reaches a score earlier wins, `compare` must return 0 only
+// if the two objects have same Uuid(). Pass WithUuidOrder if `compare`
+// orders objects by Uuid() only.
+func NewZSkipListFunc(compare func(a, b RankInterface) int, opts ...Option) *ZSkipList {
+	return NewSkipList[RankInterface, uint32](compare, opts...)
+}
+
+// CompareUuid order objects by Uuid() ascending
+func CompareUuid(a, b RankInterface) int {
+	return cmp.Compare(a.Uuid(), b.Uuid())
+}
+
+// CompareUuidDesc order objects by Uuid() descending
+func CompareUuidDesc(a, b RankInterface) int {
+	return cmp.Compare(b.Uuid(), a.Uuid())
+}
+
+// compareTo compare node `x` with element (score, obj), this is the one
+// ordering definition shared by all traversal functions.
+func (zsl *SkipList[K, S]) compareTo(x *SkipListNode[K, S], score S, obj K) int {
+	return zsl.compareElement(x.score, x.obj, score, obj)
+}
+
+// compareElement compare element (s1, o1) with (s2, o2) in list order
+func (zsl *SkipList[K, S]) compareElement(s1 S, o1 K, s2 S, o2 K) int {
+	if c := zsl.compareScore(s1, s2); c != 0 {
+		return c
+	}
+	return zsl.compare(o1, o2)
+}
+
+// compareScore compare score `s1` with `s2` in list order
+func (zsl *SkipList[K, S]) compareScore(s1, s2 S) int {
+	if zsl.descending {
+		return cmp.Compare(s2, s1)
+	}
+	return cmp.Compare(s1, s2)
+}
+
 // Returns a random level for the new skiplist node we are going to create.
-// The return value of this function is between 1 and ZSKIPLIST_MAXLEVEL
+// The return value of this function is between 1 and max level of list
 // (both inclusive), with a powerlaw-alike distribution where higher
 // levels are less likely to be returned.
-func (zsl *ZSkipList) randLevel() int {
+func (zsl *SkipList[K, S]) randLevel() int {
+	var maxLevel = len(zsl.head.level)
 	var level = 1
-	for {
-		var seed = rand.Uint32() & 0xFFFF
-		if float32(seed) < ZSKIPLIST_P*0xFFFF {
+	for level < maxLevel {
+		var seed uint32
+		if zsl.rnd != nil {
+			seed = zsl.rnd.Uint32() & 0xFFFF
+		} else {
+			seed = rand.Uint32() & 0xFFFF
+		}
+		if float64(seed) < zsl.p*0xFFFF {
 			level++
 		} else {
 			break
 		}
 	}
-	if level < ZSKIPLIST_MAXLEVEL {
-		return level
-	} else {
-		return ZSKIPLIST_MAXLEVEL
+	return level
+}
+
+// emptyClone return an empty list with the same options, tie-break policy
+// and member codec of list, log is not attached. The rand source is not
+// shared since *rand.Rand is not safe for concurrent use, the clone uses
+// the global source.
+func (zsl *SkipList[K, S]) emptyClone() *SkipList[K, S] {
+	var zero S
+	var obj K
+	return &SkipList[K, S]{
+		level:      1,
+		head:       newSkipListNode(len(zsl.head.level), zero, obj),
+		compare:    zsl.compare,
+		lexKey:     zsl.lexKey,
+		descending: zsl.descending,
+		codec:      zsl.codec,
+		logger:     zsl.logger,
+		debug:      zsl.debug,
+		p:          zsl.p,
+		uuidOrder:  zsl.uuidOrder,
+		accept:     zsl.accept,
+	}
+}
+
+// reset remove all items of list
+func (zsl *SkipList[K, S]) reset() {
+	for i := range zsl.head.level {
+		zsl.head.level[i] = zskipListLevel[K, S]{}
 	}
+	zsl.tail = nil
+	zsl.length = 0
+	zsl.level = 1
 }
 
 // Len return # of items in list
-func (zsl *ZSkipList) Len() int {
+func (zsl *SkipList[K, S]) Len() int {
 	return zsl.length
 }
 
+// MaxLevel return max level of list
+func (zsl *SkipList[K, S]) MaxLevel() int {
+	return len(zsl.head.level)
+}
+
 // Height return current level of list
-func (zsl *ZSkipList) Height() int {
+func (zsl *SkipList[K, S]) Height() int {
 	return zsl.level
 }
 
 // HeaderNode return the node after head
-func (zsl *ZSkipList) HeaderNode() *ZSkipListNode {
+func (zsl *SkipList[K, S]) HeaderNode() *SkipListNode[K, S] {
 	return zsl.head.level[0].forward
 }
 
+// Descending return true if the list is in descend order of score
+func (zsl *SkipList[K, S]) Descending() bool {
+	return zsl.descending
+}
+
 // TailNode return the tail node
-func (zsl *ZSkipList) TailNode() *ZSkipListNode {
+func (zsl *SkipList[K, S]) TailNode() *SkipListNode[K, S] {
 	return zsl.tail
 }
 
-// Insert insert an object to skiplist with score
-func (zsl *ZSkipList) Insert(score uint32, obj RankInterface) *ZSkipListNode {
-	var update [ZSKIPLIST_MAXLEVEL]*ZSkipListNode
-	var rank [ZSKIPLIST_MAXLEVEL]int
+// Insert insert an object to skiplist with score,
+// returns nil if the element is already in list.
+func (zsl *SkipList[K, S]) Insert(score S, obj K) *SkipListNode[K, S] {
+	var x, err = zsl.InsertE(score, obj)
+	if err != nil && zsl.logger != nil {
+		zsl.logger.Warn("zskiplist: insert failed", "score", score, "obj", obj, "err", err)
+	}
+	return x
+}
+
+// InsertE insert an object to skiplist with score,
+// returns ErrDuplicate if the element is already in list, or ErrNotLexMember
+// if a lex list does not accept the object. If the insertion is not written
+// to the attached log, the node is returned with an error wraps ErrLog.
+func (zsl *SkipList[K, S]) InsertE(score S, obj K) (*SkipListNode[K, S], error) {
+	if err := zsl.acceptMember(obj); err != nil {
+		return nil, err
+	}
+	var x = newSkipListNode(zsl.randLevel(), score, obj)
+	if !zsl.insertNode(x) {
+		return nil, ErrDuplicate
+	}
+	var err = zsl.logInsert(score, obj)
+	zsl.check()
+	return x, err
+}
 
-	var x = zsl.head
+// acceptMember return an error if `obj` cannot be inserted to list
+func (zsl *SkipList[K, S]) acceptMember(obj K) error {
+	if zsl.accept == nil {
+		return nil
+	}
+	return zsl.accept(obj)
+}
+
+// finger is the update/rank vector of a search, update[i] is the last node
+// before the position at level i and rank[i] is its rank. A following search
+// continues from it rather than from head.
+type finger[K comparable, S cmp.Ordered] struct {
+	update [ZSKIPLIST_MAXLEVEL_LIMIT]*SkipListNode[K, S]
+	rank   [ZSKIPLIST_MAXLEVEL_LIMIT]int
+}
+
+// seek move finger `f` to the position of element (score, obj), it must not
+// be before the last position of `f` in list order. A zero finger searches
+// from head.
+func (zsl *SkipList[K, S]) seek(f *finger[K, S], score S, obj K) {
+	var x, rank = zsl.head, 0
 	for i := zsl.level - 1; i >= 0; i-- {
-		// store rank that is crossed to reach the insert position
-		if i != zsl.level-1 {
-			rank[i] = rank[i+1]
+		// start from the finger if it is ahead
+		if f.rank[i] > rank {
+			x, rank = f.update[i], f.rank[i]
 		}
 		for x.level[i].forward != nil &&
-			(x.level[i].forward.Score < score ||
-				(x.level[i].forward.Score == score &&
-					x.level[i].forward.Obj.Uuid() < obj.Uuid())) {
-			rank[i] += x.level[i].span
+			zsl.compareTo(x.level[i].forward, score, obj) < 0 {
+			rank += x.level[i].span
 			x = x.level[i].forward
 		}
-		update[i] = x
+		f.update[i] = x
+		f.rank[i] = rank
+	}
+}
+
+// insertNode link node `x` into list by its score and object, the level of
+// `x` is kept. Returns false if the element is already in list.
+func (zsl *SkipList[K, S]) insertNode(x *SkipListNode[K, S]) bool {
+	var f finger[K, S]
+	zsl.seek(&f, x.score, x.obj)
+	return zsl.linkNode(x, &f)
+}
+
+// linkNode link node `x` at the position of finger `f`, returns false if the
+// element is already in list.
+func (zsl *SkipList[K, S]) linkNode(x *SkipListNode[K, S], f *finger[K, S]) bool {
+	var update, rank = &f.update, &f.rank
+	// we allow duplicated scores, but the re-insertion of same score and
+	// object would corrupt ranks, the element must be the next if it is
+	// already inside.
+	if next := update[0].level[0].forward; next != nil && zsl.compareTo(next, x.score, x.obj) == 0 {
+		return false
 	}
-	// we assume the key is not already inside, since we allow duplicated
-	// scores, and the re-insertion of score and redis object should never
-	// happen since the caller should test in the hash table  if the element
-	// is already inside or not.
-	var level = zsl.randLevel()
+	var level = len(x.level)
 	if level > zsl.level {
 		for i := zsl.level; i < level; i++ {
 			rank[i] = 0
@@ -151,9 +373,12 @@ func (zsl *ZSkipList) Insert(score uint32, obj RankInterface) *ZSkipListNode {
 		}
 		zsl.level = level
 	}
-	x = newZSkipListNode(level, score, obj)
 	for i := 0; i < level; i++ {
 		x.level[i].forward = update[i].level[i].forward
+		x.level[i].backward = update[i]
+		if x.level[i].forward != nil {
+			x.level[i].forward.level[i].backward = x
+		}
 		update[i].level[i].forward = x
 
 		// update span covered by update[i] as x is inserted here
@@ -166,6 +391,8 @@ func (zsl *ZSkipList) Insert(score uint32, obj RankInterface) *ZSkipListNode {
 	}
 	if update[0] != zsl.head {
 		x.backward = update[0]
+	} else {
+		x.backward = nil
 	}
 	if x.level[0].forward != nil {
 		x.level[0].forward.backward = x
@@ -173,14 +400,17 @@ func (zsl *ZSkipList) Insert(score uint32, obj RankInterface) *ZSkipListNode {
 		zsl.tail = x
 	}
 	zsl.length++
-	return x
+	return true
 }
 
-func (zsl *ZSkipList) deleteNode(x *ZSkipListNode, update []*ZSkipListNode) {
+func (zsl *SkipList[K, S]) deleteNode(x *SkipListNode[K, S], update []*SkipListNode[K, S]) {
 	for i := 0; i < zsl.level; i++ {
 		if update[i].level[i].forward == x {
 			update[i].level[i].span += x.level[i].span - 1
 			update[i].level[i].forward = x.level[i].forward
+			if x.level[i].forward != nil {
+				x.level[i].forward.level[i].backward = update[i]
+			}
 		} else {
 			update[i].level[i].span -= 1
 		}
@@ -196,15 +426,28 @@ func (zsl *ZSkipList) deleteNode(x *ZSkipListNode, update []*ZSkipListNode) {
 	zsl.length--
 }
 
-// Delete delete an element with matching score/object from the skiplist
-func (zsl *ZSkipList) Delete(score uint32, obj RankInterface) *ZSkipListNode {
-	var update [ZSKIPLIST_MAXLEVEL]*ZSkipListNode
+// Delete delete an element with matching score/object from the skiplist,
+// returns nil if not found.
+func (zsl *SkipList[K, S]) Delete(score S, obj K) *SkipListNode[K, S] {
+	var x, err = zsl.DeleteE(score, obj)
+	if err != nil && zsl.logger != nil {
+		zsl.logger.Warn("zskiplist: delete failed", "score", score, "obj", obj, "err", err)
+	}
+	return x
+}
+
+// DeleteE delete an element with matching score/object from the skiplist,
+// returns ErrNotFound if not found, or ErrScoreMismatch if the object is
+// found next to the position of `score` but with a different score.
+// A list cannot tell a wrong score from a missing object in general, use
+// ZSet to delete by uuid without score. If the deletion is not written to
+// the attached log, the node is returned with an error wraps ErrLog.
+func (zsl *SkipList[K, S]) DeleteE(score S, obj K) (*SkipListNode[K, S], error) {
+	var update [ZSKIPLIST_MAXLEVEL_LIMIT]*SkipListNode[K, S]
 	var x = zsl.head
 	for i := zsl.level - 1; i >= 0; i-- {
 		for x.level[i].forward != nil &&
-			(x.level[i].forward.Score < score ||
-				(x.level[i].forward.Score == score &&
-					x.level[i].forward.Obj.Uuid() < obj.Uuid())) {
+			zsl.compareTo(x.level[i].forward, score, obj) < 0 {
 			x = x.level[i].forward
 		}
 		update[i] = x
@@ -212,43 +455,105 @@ func (zsl *ZSkipList) Delete(score uint32, obj RankInterface) *ZSkipListNode {
 
 	// We may have multiple elements with the same score, what we need
 	// is to find the element with both the right score and object.
+	var prev = x
 	x = x.level[0].forward
-	if x != nil {
-		if score == x.Score && x.Obj.Uuid() == obj.Uuid() {
-			zsl.deleteNode(x, update[0:])
-			return x
+	if x != nil && zsl.compareTo(x, score, obj) == 0 {
+		zsl.deleteNode(x, update[0:])
+		var err = zsl.logDelete(score, obj)
+		zsl.check()
+		return x, err
+	}
+	if (x != nil && zsl.compare(x.obj, obj) == 0) ||
+		(prev != zsl.head && zsl.compare(prev.obj, obj) == 0) {
+		return nil, ErrScoreMismatch
+	}
+	return nil, ErrNotFound
+}
+
+// UpdateScore update the score of an element from `curScore` to `newScore`,
+// the element must exist and match `curScore`, and the new element must not
+// be already inside, return nil otherwise.
+// If the node is still between its neighbours after the update, the score is
+// changed in place, otherwise the node is relinked without reallocation by a
+// finger search from its old position, see UpdateNodeScore.
+func (zsl *SkipList[K, S]) UpdateScore(curScore S, obj K, newScore S) *SkipListNode[K, S] {
+	var x, _ = zsl.update(curScore, obj, newScore, obj)
+	return x
+}
+
+// UpdateScoreE is UpdateScore returns ErrNotFound if the element is not in
+// list, or ErrDuplicate if the new element is already inside. If the update
+// is not written to the attached log, the node is returned with an error
+// wraps ErrLog.
+func (zsl *SkipList[K, S]) UpdateScoreE(curScore S, obj K, newScore S) (*SkipListNode[K, S], error) {
+	return zsl.update(curScore, obj, newScore, obj)
+}
+
+// update move element (curScore, obj) to (newScore, newObj), `newObj` may
+// have a different tie-break order than `obj` but must be the same member.
+func (zsl *SkipList[K, S]) update(curScore S, obj K, newScore S, newObj K) (*SkipListNode[K, S], error) {
+	var x = zsl.head
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil &&
+			zsl.compareTo(x.level[i].forward, curScore, obj) < 0 {
+			x = x.level[i].forward
 		}
-		log.Printf("zskiplist need delete %v, but found %v\n", obj, x.Obj)
 	}
-	return nil // not found
+	x = x.level[0].forward
+	if x == nil || zsl.compareTo(x, curScore, obj) != 0 {
+		return nil, ErrNotFound
+	}
+	return zsl.updateNode(x, newScore, newObj)
 }
 
 // GetRank Find the rank for an element by both score and key.
 // Returns 0 when the element cannot be found, rank otherwise.
 // Note that the rank is 1-based due to the span of zsl->header to the first element.
-func (zsl *ZSkipList) GetRank(score uint32, obj RankInterface) int {
+func (zsl *SkipList[K, S]) GetRank(score S, obj K) int {
 	var rank = 0
 	var x = zsl.head
 	for i := zsl.level - 1; i >= 0; i-- {
 		for x.level[i].forward != nil &&
-			(x.level[i].forward.Score < score ||
-				(x.level[i].forward.Score == score &&
-					x.level[i].forward.Obj.Uuid() <= obj.Uuid())) {
+			zsl.compareTo(x.level[i].forward, score, obj) <= 0 {
 			rank += x.level[i].span
 			x = x.level[i].forward
 		}
 
-		// x might be equal to zsl->header, so test if obj is non-nil
-		if x.Obj != nil && x.Obj.Uuid() == obj.Uuid() {
+		// x might be equal to zsl->header, so test if x is not head
+		if x != zsl.head && zsl.compare(x.obj, obj) == 0 {
 			return rank
 		}
 	}
 	return 0
 }
 
+// RankOf return the 1-based rank of node `x` in list by walking forward to
+// tail along the highest levels and summing spans, which is O(log N)
+// expected without any comparison. The result is undefined if `x` is not
+// in list.
+func (zsl *SkipList[K, S]) RankOf(x *SkipListNode[K, S]) int {
+	var after = 0 // # of elements after x
+	for x != nil {
+		var i = len(x.level) - 1
+		after += x.level[i].span
+		x = x.level[i].forward
+	}
+	return zsl.length - after
+}
+
+// RevRank return the 1-based rank of an element in reverse list order,
+// i.e. the tail element is ranked 1. Returns 0 when the element cannot be found.
+func (zsl *SkipList[K, S]) RevRank(score S, obj K) int {
+	var rank = zsl.GetRank(score, obj)
+	if rank == 0 {
+		return 0
+	}
+	return zsl.length - rank + 1
+}
+
 // GetElementByRank Finds an element by its rank.
 // The rank argument needs to be 1-based.
-func (zsl *ZSkipList) GetElementByRank(rank int) *ZSkipListNode {
+func (zsl *SkipList[K, S]) GetElementByRank(rank int) *SkipListNode[K, S] {
 	var tranversed int = 0
 	var x = zsl.head
 	for i := zsl.level - 1; i >= 0; i-- {
@@ -263,12 +568,14 @@ func (zsl *ZSkipList) GetElementByRank(rank int) *ZSkipListNode {
 	return nil
 }
 
-// GetTopRankRange get top score of N elements
-func (zsl *ZSkipList) GetTopRankValueRange(n int) []RankInterface {
-	var ranks = make([]RankInterface, 0, n)
+// GetTopRankValueRange get N elements from tail, which are the top scores
+// of an ascending list but the lowest scores of a descending list, use
+// RangeByRank(0, n-1, false) for the top of a descending list.
+func (zsl *SkipList[K, S]) GetTopRankValueRange(n int) []K {
+	var ranks = make([]K, 0, n)
 	var x = zsl.tail
 	for x != nil && n > 0 {
-		ranks = append(ranks, x.Obj)
+		ranks = append(ranks, x.obj)
 		n--
 		x = x.backward
 	}
@@ -276,35 +583,38 @@ func (zsl *ZSkipList) GetTopRankValueRange(n int) []RankInterface {
 }
 
 // GetNearByRankRange get range near to rank
-func (zsl *ZSkipList) GetNearByRankRange(rank, up, down int) []RankInterface {
+func (zsl *SkipList[K, S]) GetNearByRankRange(rank, up, down int) []K {
 	var target = zsl.GetElementByRank(rank)
 	if target == nil {
 		return nil
 	}
-	var ranks = make([]RankInterface, 0, up+down+1)
+	var ranks = make([]K, 0, up+down+1)
 	var x = target.backward
 	for x != nil && up > 0 {
-		ranks = append(ranks, x.Obj)
+		ranks = append(ranks, x.obj)
 		up--
 		x = x.backward
 	}
-	ranks = append(ranks, target.Obj)
+	ranks = append(ranks, target.obj)
 	x = target.level[0].forward
 	for x != nil && down > 0 {
-		ranks = append(ranks, x.Obj)
+		ranks = append(ranks, x.obj)
 		down--
 		x = x.level[0].forward
 	}
 	return ranks
 }
 
-// Walk iterate list by `fn` with max `loop`
-func (zsl *ZSkipList) Walk(startTail bool, fn func(int, RankInterface) bool) {
+// Walk iterate list by `fn` from tail if `startTail` or from head, until
+// `fn` returns false. The rank passed to `fn` is counted from tail (tail is
+// 1), which is the top score of an ascending list but the lowest score of
+// a descending list, use Iterator for ranks same as GetRank.
+func (zsl *SkipList[K, S]) Walk(startTail bool, fn func(int, K) bool) {
 	if startTail { // from tail to head
 		var rank = 1
 		var node = zsl.tail
 		for node != nil {
-			if !fn(rank, node.Obj) {
+			if !fn(rank, node.obj) {
 				break
 			}
 			node = node.backward
@@ -314,7 +624,7 @@ func (zsl *ZSkipList) Walk(startTail bool, fn func(int, RankInterface) bool) {
 		var rank = zsl.length
 		var node = zsl.head.level[0].forward
 		for node != nil {
-			if !fn(rank, node.Obj) {
+			if !fn(rank, node.obj) {
 				break
 			}
 			rank--
@@ -323,14 +633,14 @@ func (zsl *ZSkipList) Walk(startTail bool, fn func(int, RankInterface) bool) {
 	}
 }
 
-func (zsl ZSkipList) String() string {
+func (zsl SkipList[K, S]) String() string {
 	var buf bytes.Buffer
 	zsl.Dump(&buf)
 	return buf.String()
 }
 
 // Dump dump whole list to w, mostly for debug usage
-func (zsl *ZSkipList) Dump(w io.Writer) {
+func (zsl *SkipList[K, S]) Dump(w io.Writer) {
 	var x = zsl.head
 	// dump header
 	var line bytes.Buffer
@@ -367,10 +677,10 @@ func (zsl *ZSkipList) Dump(w io.Writer) {
 	fmt.Fprintf(w, "\n")
 }
 
-func (zsl *ZSkipList) dumpNode(w io.Writer, node *ZSkipListNode, count int) {
+func (zsl *SkipList[K, S]) dumpNode(w io.Writer, node *SkipListNode[K, S], count int) {
 	var line bytes.Buffer
-	var uuid = fmt.Sprintf("%d", node.Obj.Uuid())
-	n, _ := fmt.Fprintf(w, "<%s %6d %4d> ", uuid, node.Score, count)
+	var uuid = memberString(node.obj)
+	n, _ := fmt.Fprintf(w, "<%s %6v %4d> ", uuid, node.score, count)
 	prePadding(&line, n)
 	for i := 0; i < zsl.level; i++ {
 		if i < len(node.level) {
@@ -388,12 +698,12 @@ func (zsl *ZSkipList) dumpNode(w io.Writer, node *ZSkipListNode, count int) {
 	line.WriteTo(w)
 }
 
-func shouldLinkVertical(head, node *ZSkipListNode, level int) bool {
+func shouldLinkVertical[K comparable, S cmp.Ordered](head, node *SkipListNode[K, S], level int) bool {
 	if node.backward == nil { // first element
 		return head.level[level].span >= 1
 	}
 	var tranversed = 0
-	var prev *ZSkipListNode
+	var prev *SkipListNode[K, S]
 	var x = node.backward
 	for x != nil {
 		if level >= len(x.level) {
@@ -412,6 +722,14 @@ func shouldLinkVertical(head, node *ZSkipListNode, level int) bool {
 	return false
 }
 
+// memberString format a member for dump, RankInterface is shown by its uuid
+func memberString(obj any) string {
+	if v, ok := obj.(RankInterface); ok {
+		return fmt.Sprintf("%d", v.Uuid())
+	}
+	return fmt.Sprintf("%v", obj)
+}
+
 func prePadding(line *bytes.Buffer, n int) {
 	for i := 0; i < n; i++ {
 		line.WriteByte(' ')
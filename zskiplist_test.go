@@ -7,11 +7,15 @@
 package zskiplist
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"math/rand"
 	"os"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 )
@@ -63,7 +67,7 @@ func checkDupObject(zsl *ZSkipList, t *testing.T) {
 	var node = zsl.HeaderNode().Next()
 	for node != nil {
 		rank--
-		var player = node.Obj.(*testPlayer)
+		var player = node.Member().(*testPlayer)
 		if _, found := set[player.Uid]; found {
 			t.Fatalf("Duplicate rank object found: %d, %d", rank, player.Uid)
 		}
@@ -154,7 +158,7 @@ func TestZSkipListInsertRemove(t *testing.T) {
 			if node == nil {
 				t.Fatalf("delete item[%d-%d] failed", v.Populace, v.Uid)
 			}
-			if brief := node.Obj.(*testPlayer); brief.Uid != v.Uid {
+			if brief := node.Member().(*testPlayer); brief.Uid != v.Uid {
 				t.Fatalf("delete item, %d not equal to %d", brief.Uid, v.Uid)
 			}
 		}
@@ -191,7 +195,7 @@ func TestZSkipListChangedInsert(t *testing.T) {
 		if node == nil {
 			t.Fatalf("delete set item[%d-%d] failed", v.Populace, v.Uid)
 		}
-		if player := node.Obj.(*testPlayer); player.Uid != v.Uid {
+		if player := node.Member().(*testPlayer); player.Uid != v.Uid {
 			t.Fatalf("delete set item, %d not equal to %d", player.Uid, v.Uid)
 		}
 	}
@@ -264,7 +268,7 @@ func TestZSkipListUpdateGetRank(t *testing.T) {
 					t.Fatalf("%v GetElementByRank return nil: %d", v, rank)
 					break
 				}
-				var player = node.Obj.(*testPlayer)
+				var player = node.Member().(*testPlayer)
 				if player.Populace == v.Populace {
 					// OK, cuz skip list sort is not stable
 				} else {
@@ -278,6 +282,215 @@ func TestZSkipListUpdateGetRank(t *testing.T) {
 	}
 }
 
+func TestZSkipListUpdateScore(t *testing.T) {
+	const units = 10000
+	var set = makeTestPlayers(units, 1000, true)
+	var zsl = NewZSkipList()
+	var nodes = make(map[uint64]*ZSkipListNode, units)
+	for _, v := range set {
+		nodes[v.Uid] = zsl.Insert(v.Populace, v)
+	}
+	for i := 0; i < 10; i++ {
+		for _, v := range set {
+			var oldScore = v.Populace
+			v.Populace = uint32(rand.Int()%1000) + 1
+			var node = zsl.UpdateScore(oldScore, v, v.Populace)
+			if node == nil {
+				t.Fatalf("update item[%d-%d] failed", v.Uid, oldScore)
+			}
+			if node != nodes[v.Uid] {
+				t.Fatalf("update item[%d-%d] reallocated node", v.Uid, oldScore)
+			}
+		}
+	}
+	if zsl.Len() != units {
+		t.Fatalf("unexpected skiplist element count, %d != %d", zsl.Len(), units)
+	}
+
+	var ranks = mapToSlice(set)
+	sort.Slice(ranks, func(i, j int) bool {
+		if ranks[i].Populace != ranks[j].Populace {
+			return ranks[i].Populace < ranks[j].Populace
+		}
+		return ranks[i].Uid < ranks[j].Uid
+	})
+	for i, v := range ranks {
+		if rank := zsl.GetRank(v.Populace, v); rank != i+1 {
+			t.Fatalf("%v not equal at rank, %d != %d", v, rank, i+1)
+		}
+		if node := zsl.GetElementByRank(i + 1); node.Member() != v {
+			t.Fatalf("element at rank %d: %v != %v", i+1, node.Member(), v)
+		}
+	}
+	var prev *ZSkipListNode
+	for node := zsl.HeaderNode(); node != nil; node = node.Next() {
+		if node.Before() != prev {
+			t.Fatalf("broken backward link at %v", node.Member())
+		}
+		prev = node
+	}
+	if zsl.TailNode() != prev {
+		t.Fatalf("broken tail node")
+	}
+	if err := zsl.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if zsl.UpdateScore(0, &testPlayer{Uid: 1}, 1) != nil {
+		t.Fatalf("update of missing item should fail")
+	}
+}
+
+func TestZSkipListErrors(t *testing.T) {
+	var buf bytes.Buffer
+	var zsl = NewZSkipList(WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+	for i := 1; i <= 10; i++ {
+		if _, err := zsl.InsertE(uint32(i*10), &testPlayer{Uid: uint64(i)}); err != nil {
+			t.Fatalf("InsertE: %v", err)
+		}
+	}
+	if _, err := zsl.InsertE(50, &testPlayer{Uid: 5}); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("InsertE duplicate: %v", err)
+	}
+	if zsl.Insert(50, &testPlayer{Uid: 5}) != nil || zsl.Len() != 10 {
+		t.Fatalf("Insert duplicate should fail")
+	}
+	if !strings.Contains(buf.String(), ErrDuplicate.Error()) {
+		t.Fatalf("duplicate insert not logged: %s", buf.String())
+	}
+	if _, err := zsl.DeleteE(51, &testPlayer{Uid: 5}); !errors.Is(err, ErrScoreMismatch) {
+		t.Fatalf("DeleteE wrong score: %v", err)
+	}
+	if _, err := zsl.DeleteE(50, &testPlayer{Uid: 11}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("DeleteE missing: %v", err)
+	}
+	if node, err := zsl.DeleteE(50, &testPlayer{Uid: 5}); err != nil || node.Member().Uuid() != 5 {
+		t.Fatalf("DeleteE: %v", err)
+	}
+	if zsl.UpdateScore(40, &testPlayer{Uid: 4}, 60) == nil || zsl.GetRank(60, &testPlayer{Uid: 4}) != 4 {
+		t.Fatalf("UpdateScore failed")
+	}
+
+	buf.Reset()
+	zsl = NewZSkipList(WithLogger(nil))
+	if zsl.Delete(1, &testPlayer{Uid: 1}) != nil || buf.Len() != 0 {
+		t.Fatalf("unexpected log with nil logger")
+	}
+}
+
+func TestZSkipListOptions(t *testing.T) {
+	var build = func(opts ...Option) *ZSkipList {
+		var zsl = NewZSkipList(opts...)
+		for i := 0; i < 5000; i++ {
+			zsl.Insert(uint32(i%100), &testPlayer{Uid: uint64(i)})
+		}
+		if err := zsl.Validate(); err != nil {
+			t.Fatalf("Validate: %v", err)
+		}
+		return zsl
+	}
+	var a = build(WithRandSource(rand.NewSource(42)))
+	var b = build(WithRandSource(rand.NewSource(42)))
+	if a.String() != b.String() {
+		t.Fatalf("layout of same rand source is not deterministic")
+	}
+
+	var zsl = build(WithMaxLevel(ZSKIPLIST_MAXLEVEL_LIMIT), WithP(0.5), WithRandSource(rand.NewSource(1)))
+	if zsl.MaxLevel() != ZSKIPLIST_MAXLEVEL_LIMIT || zsl.Height() <= ZSKIPLIST_MAXLEVEL {
+		t.Fatalf("unexpected height %d of max level %d", zsl.Height(), zsl.MaxLevel())
+	}
+	zsl = build(WithMaxLevel(2))
+	if zsl.Height() > 2 {
+		t.Fatalf("height %d exceeds max level 2", zsl.Height())
+	}
+
+	for _, opt := range []Option{WithMaxLevel(0), WithMaxLevel(ZSKIPLIST_MAXLEVEL_LIMIT + 1), WithP(0), WithP(1)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("invalid option should panic")
+				}
+			}()
+			NewZSkipList(opt)
+		}()
+	}
+}
+
+func TestZSkipListRankOf(t *testing.T) {
+	const units = 5000
+	var set = makeTestPlayers(units, 1000, true)
+	for _, opts := range [][]Option{nil, {WithDescending()}} {
+		var zsl = NewZSkipList(opts...)
+		var nodes = make(map[uint64]*ZSkipListNode, units)
+		for _, v := range set {
+			nodes[v.Uid] = zsl.Insert(v.Populace, v)
+		}
+		var n = 0
+		for _, v := range set {
+			if n++; n%3 == 0 {
+				zsl.Delete(v.Populace, v)
+				delete(nodes, v.Uid)
+			} else if n%3 == 1 {
+				zsl.UpdateNodeScore(nodes[v.Uid], uint32(rand.Int()%1000))
+			}
+		}
+		for _, node := range nodes {
+			var rank = zsl.GetRank(node.Score(), node.Member())
+			if zsl.RankOf(node) != rank || node.Rank() != rank {
+				t.Fatalf("rank of %v: %d %d != %d", node.Member(), zsl.RankOf(node), node.Rank(), rank)
+			}
+		}
+		var rank = 0
+		for x := zsl.HeaderNode(); x != nil; x = x.Next() {
+			if rank++; zsl.RankOf(x) != rank || x.Rank() != rank {
+				t.Fatalf("rank of node %d mismatch", rank)
+			}
+		}
+	}
+}
+
+func TestSkipListGenericScore(t *testing.T) {
+	var zsl = NewOrderedSkipList[string, int64]()
+	var scores = map[string]int64{
+		"alice": -20,
+		"bob":   15,
+		"carol": -20,
+		"dave":  0,
+	}
+	for k, v := range scores {
+		if node := zsl.Insert(v, k); node == nil {
+			t.Fatalf("insert item[%s-%d] failed", k, v)
+		}
+	}
+	var expected = []string{"alice", "carol", "dave", "bob"}
+	for i, name := range expected {
+		if rank := zsl.GetRank(scores[name], name); rank != i+1 {
+			t.Fatalf("rank of %s: %d != %d", name, rank, i+1)
+		}
+		if node := zsl.GetElementByRank(i + 1); node == nil || node.Member() != name {
+			t.Fatalf("element at rank %d: %v != %s", i+1, node, name)
+		}
+	}
+	if node := zsl.Delete(scores["carol"], "carol"); node == nil || node.Member() != "carol" {
+		t.Fatalf("delete carol failed")
+	}
+	if rank := zsl.GetRank(scores["dave"], "dave"); rank != 2 {
+		t.Fatalf("rank of dave after delete: %d != 2", rank)
+	}
+
+	var fzsl = NewOrderedSkipList[uint64, float64]()
+	fzsl.Insert(1.5, 3)
+	fzsl.Insert(-0.25, 2)
+	fzsl.Insert(1.5, 1)
+	var got []uint64
+	fzsl.Walk(true, func(rank int, v uint64) bool {
+		got = append(got, v)
+		return true
+	})
+	if fmt.Sprint(got) != "[3 1 2]" {
+		t.Fatalf("unexpected walk order: %v", got)
+	}
+}
+
 func BenchmarkZSkipListInsert(b *testing.B) {
 	b.StopTimer()
 	var zsl = NewZSkipList()